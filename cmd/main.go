@@ -13,7 +13,6 @@ import (
 	"github.com/Alonza0314/nf-example/pkg/service"
 	"github.com/urfave/cli"
 
-	logger_util "github.com/free5gc/util/logger"
 	"github.com/free5gc/util/version"
 )
 
@@ -39,9 +38,14 @@ func main() {
 			Name:  "log, l",
 			Usage: "Output NF log to `FILE`",
 		},
+		cli.BoolFlag{
+			Name:  "skip-seed",
+			Usage: "Skip loading the seed data from configuration",
+		},
 	}
 	if err := app.Run(os.Args); err != nil {
 		logger.MainLog.Errorf("ANYA Run Error: %v\n", err)
+		os.Exit(1)
 	}
 }
 
@@ -56,6 +60,10 @@ func action(cliCtx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	if cliCtx.Bool("skip-seed") && cfg.Configuration.Seed != nil {
+		logger.MainLog.Infoln("Skip loading seed data")
+		cfg.Configuration.Seed = nil
+	}
 	factory.NfConfig = cfg
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -73,16 +81,14 @@ func action(cliCtx *cli.Context) error {
 	}
 	NF = nf
 
-	nf.Start()
-
-	return nil
+	return nf.Start()
 }
 
 func initLogFile(logNfPath []string) (string, error) {
 	logTlsKeyPath := ""
 
 	for _, path := range logNfPath {
-		if err := logger_util.LogFileHook(logger.Log, path); err != nil {
+		if _, _, err := logger.AddFileOutput(path, 0, 0); err != nil {
 			return "", err
 		}
 
@@ -1,15 +1,27 @@
 package context
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"sort"
+	"sync"
 
+	"github.com/Alonza0314/nf-example/internal/health"
 	"github.com/Alonza0314/nf-example/internal/logger"
 	"github.com/Alonza0314/nf-example/pkg/factory"
-	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
 
 	"github.com/free5gc/openapi/models"
 )
 
+var (
+	// ErrContextNotInitialized is returned by accessors of a context InitNfContext has not set up.
+	ErrContextNotInitialized = errors.New("NF context is not initialized")
+	// ErrCharacterNotFound is returned by LookupCharacter for unknown characters.
+	ErrCharacterNotFound = errors.New("not found in SPYxFAMILY")
+)
+
 type NFContext struct {
 	NfId        string
 	Name        string
@@ -18,29 +30,65 @@ type NFContext struct {
 	SBIPort     int
 
 	SpyFamilyData map[string]string
+
+	// Health holds the startup dependency checks, nil when none ran.
+	Health *health.Checker
+
+	maintenance   Maintenance
+	maintenanceMu sync.RWMutex
+
+	features   map[string]bool
+	featuresMu sync.RWMutex
+}
+
+type Maintenance struct {
+	Enabled    bool   `json:"enabled"`
+	Message    string `json:"message,omitempty"`
+	RetryAfter int    `json:"retryAfter"`
 }
 
-var nfContext = NFContext{}
+var nfContext = &NFContext{}
 
+// InitNfContext builds the NF context from factory.NfConfig and makes it the one GetSelf returns.
 func InitNfContext() {
-	cfg := factory.NfConfig
+	nfContext = NewNfContext(factory.NfConfig)
+}
+
+// NewNfContext builds an NF context from cfg without touching the one returned by GetSelf.
+func NewNfContext(cfg *factory.Config) *NFContext {
+	nfCtx := &NFContext{}
 
-	nfContext.NfId = uuid.New().String()
-	nfContext.Name = "ANYA"
+	nfCtx.NfId = loadNfId(cfg.Configuration.StateFile)
+	nfCtx.Name = "ANYA"
 
-	nfContext.UriScheme = cfg.Configuration.Sbi.Scheme
-	nfContext.SBIPort = cfg.Configuration.Sbi.Port
-	nfContext.BindingIPv4 = os.Getenv(cfg.Configuration.Sbi.BindingIPv4)
-	if nfContext.BindingIPv4 != "" {
+	nfCtx.UriScheme = cfg.Configuration.Sbi.Scheme
+	nfCtx.SBIPort = cfg.Configuration.Sbi.Port
+	nfCtx.BindingIPv4 = os.Getenv(cfg.Configuration.Sbi.BindingIPv4)
+	if nfCtx.BindingIPv4 != "" {
 		logger.CtxLog.Info("Parsing ServerIPv4 address from ENV Variable.")
 	} else {
-		nfContext.BindingIPv4 = cfg.Configuration.Sbi.BindingIPv4
-		if nfContext.BindingIPv4 == "" {
+		nfCtx.BindingIPv4 = cfg.Configuration.Sbi.BindingIPv4
+		if nfCtx.BindingIPv4 == "" {
 			logger.CtxLog.Warn("Error parsing ServerIPv4 address as string. Using the 0.0.0.0 address as default.")
-			nfContext.BindingIPv4 = "0.0.0.0"
+			nfCtx.BindingIPv4 = "0.0.0.0"
 		}
 	}
-	nfContext.SpyFamilyData = map[string]string{
+	maintenance := Maintenance{RetryAfter: factory.NfDefaultMaintenanceRetryAfter}
+	if cfgMaintenance := cfg.Configuration.Maintenance; cfgMaintenance != nil {
+		maintenance.Enabled = cfgMaintenance.Enable
+		maintenance.Message = cfgMaintenance.Message
+		if cfgMaintenance.RetryAfter > 0 {
+			maintenance.RetryAfter = cfgMaintenance.RetryAfter
+		}
+	}
+	nfCtx.SetMaintenance(maintenance)
+	if maintenance.Enabled {
+		logger.CtxLog.Warnf("Maintenance mode is enabled at startup: %s", maintenance.Message)
+	}
+
+	nfCtx.SetFeatures(cfg.Configuration.Features)
+
+	nfCtx.SpyFamilyData = map[string]string{
 		"Loid":   "Forger",
 		"Anya":   "Forger",
 		"Yor":    "Forger",
@@ -57,8 +105,102 @@ func InitNfContext() {
 		"Henry":  "Henderson",
 		"Martha": "Marriott",
 	}
+	return nfCtx
 }
 
 func GetSelf() *NFContext {
-	return &nfContext
+	return nfContext
+}
+
+// CheckInitialized returns ErrContextNotInitialized for a nil context or one without its stores.
+func (c *NFContext) CheckInitialized() error {
+	if c == nil || c.SpyFamilyData == nil {
+		return ErrContextNotInitialized
+	}
+	return nil
+}
+
+// NormalizeName puts a name in Unicode NFC, the form character names are stored and looked up in,
+// so "Café" matches whether its "é" is one code point or "e" followed by a combining accent.
+func NormalizeName(name string) string {
+	return norm.NFC.String(name)
+}
+
+// LookupCharacter returns the last name of a SPYxFAMILY character.
+func (c *NFContext) LookupCharacter(firstName string) (string, error) {
+	if err := c.CheckInitialized(); err != nil {
+		return "", err
+	}
+	lastName, ok := c.SpyFamilyData[NormalizeName(firstName)]
+	if !ok {
+		return "", fmt.Errorf("[%s] %w", firstName, ErrCharacterNotFound)
+	}
+	return lastName, nil
+}
+
+// SearchCharacters returns the first names of the SPYxFAMILY characters with a last name, sorted.
+func (c *NFContext) SearchCharacters(lastName string) ([]string, error) {
+	if err := c.CheckInitialized(); err != nil {
+		return nil, err
+	}
+	lastName = NormalizeName(lastName)
+	firstNames := []string{}
+	for firstName, characterLastName := range c.SpyFamilyData {
+		if characterLastName == lastName {
+			firstNames = append(firstNames, firstName)
+		}
+	}
+	sort.Strings(firstNames)
+	return firstNames, nil
+}
+
+func (c *NFContext) GetMaintenance() Maintenance {
+	c.maintenanceMu.RLock()
+	defer c.maintenanceMu.RUnlock()
+	return c.maintenance
+}
+
+func (c *NFContext) SetMaintenance(maintenance Maintenance) {
+	c.maintenanceMu.Lock()
+	defer c.maintenanceMu.Unlock()
+	c.maintenance = maintenance
+}
+
+// FeatureEnabled reports whether a feature flag is on. Unknown flags are off.
+func (c *NFContext) FeatureEnabled(name string) bool {
+	c.featuresMu.RLock()
+	defer c.featuresMu.RUnlock()
+	return c.features[name]
+}
+
+// GetFeatures returns a copy of every feature flag that has been set.
+func (c *NFContext) GetFeatures() map[string]bool {
+	c.featuresMu.RLock()
+	defer c.featuresMu.RUnlock()
+
+	features := make(map[string]bool, len(c.features))
+	for name, enabled := range c.features {
+		features[name] = enabled
+	}
+	return features
+}
+
+// SetFeatures replaces every feature flag.
+func (c *NFContext) SetFeatures(features map[string]bool) {
+	c.featuresMu.Lock()
+	defer c.featuresMu.Unlock()
+
+	c.features = make(map[string]bool, len(features))
+	for name, enabled := range features {
+		c.features[name] = enabled
+	}
+}
+
+func (c *NFContext) SetFeature(name string, enabled bool) {
+	c.featuresMu.Lock()
+	defer c.featuresMu.Unlock()
+	if c.features == nil {
+		c.features = map[string]bool{}
+	}
+	c.features[name] = enabled
 }
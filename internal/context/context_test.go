@@ -0,0 +1,81 @@
+package context_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+)
+
+func Test_LookupCharacter(t *testing.T) {
+	testCases := []struct {
+		name             string
+		nfCtx            *nf_context.NFContext
+		firstName        string
+		expectedLastName string
+		expectedError    error
+	}{
+		{
+			name:             "Found",
+			nfCtx:            &nf_context.NFContext{SpyFamilyData: map[string]string{"Anya": "Forger"}},
+			firstName:        "Anya",
+			expectedLastName: "Forger",
+		},
+		{
+			name:          "Not Found",
+			nfCtx:         &nf_context.NFContext{SpyFamilyData: map[string]string{"Anya": "Forger"}},
+			firstName:     "Andy",
+			expectedError: nf_context.ErrCharacterNotFound,
+		},
+		{
+			name:          "Nil Context",
+			nfCtx:         nil,
+			firstName:     "Anya",
+			expectedError: nf_context.ErrContextNotInitialized,
+		},
+		{
+			name:          "Context Without Stores",
+			nfCtx:         &nf_context.NFContext{},
+			firstName:     "Anya",
+			expectedError: nf_context.ErrContextNotInitialized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lastName, err := tc.nfCtx.LookupCharacter(tc.firstName)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if lastName != tc.expectedLastName {
+				t.Errorf("Expected last name %q, got %q", tc.expectedLastName, lastName)
+			}
+		})
+	}
+}
+
+func Test_SearchCharacters(t *testing.T) {
+	nfCtx := &nf_context.NFContext{SpyFamilyData: map[string]string{
+		"Yor":    "Forger",
+		"Anya":   "Forger",
+		"Loid":   "Forger",
+		"Damian": "Desmond",
+	}}
+
+	firstNames, err := nfCtx.SearchCharacters("Forger")
+	if err != nil {
+		t.Fatalf("Failed to search characters: %s", err)
+	}
+	if expected := []string{"Anya", "Loid", "Yor"}; !slices.Equal(firstNames, expected) {
+		t.Errorf("Expected %v, got %v", expected, firstNames)
+	}
+
+	if firstNames, err = nfCtx.SearchCharacters("Briar"); err != nil || len(firstNames) != 0 {
+		t.Errorf("Expected no characters, got %v (%v)", firstNames, err)
+	}
+
+	if _, err = (&nf_context.NFContext{}).SearchCharacters("Forger"); !errors.Is(err, nf_context.ErrContextNotInitialized) {
+		t.Errorf("Expected error %v, got %v", nf_context.ErrContextNotInitialized, err)
+	}
+}
@@ -0,0 +1,65 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+// LoadSeed adds the characters listed inline in the seed config and in its seed file.
+// The whole seed is validated first, so nothing is loaded if any entry is invalid or already exists.
+func (c *NFContext) LoadSeed(seed *factory.Seed) error {
+	if seed == nil {
+		return nil
+	}
+
+	characters := append([]factory.SeedCharacter{}, seed.Characters...)
+	if seed.File != "" {
+		fileSeed, err := readSeedFile(seed.File)
+		if err != nil {
+			return err
+		}
+		characters = append(characters, fileSeed.Characters...)
+	}
+
+	seen := make(map[string]bool, len(characters))
+	for i := range characters {
+		characters[i].FirstName = NormalizeName(characters[i].FirstName)
+	}
+	for i, character := range characters {
+		if strings.TrimSpace(character.FirstName) == "" || strings.TrimSpace(character.LastName) == "" {
+			return fmt.Errorf("seed character #%d: firstName and lastName are required", i+1)
+		}
+		if _, exists := c.SpyFamilyData[character.FirstName]; exists || seen[character.FirstName] {
+			return fmt.Errorf("seed character #%d: duplicate character [%s]", i+1, character.FirstName)
+		}
+		seen[character.FirstName] = true
+	}
+
+	if c.SpyFamilyData == nil {
+		c.SpyFamilyData = make(map[string]string, len(characters))
+	}
+	for _, character := range characters {
+		c.SpyFamilyData[character.FirstName] = character.LastName
+	}
+	logger.CtxLog.Infof("Seeded %d SPYxFAMILY characters", len(characters))
+	return nil
+}
+
+func readSeedFile(path string) (*factory.Seed, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read seed file failed: %w", err)
+	}
+
+	seed := &factory.Seed{}
+	if err = json.Unmarshal(content, seed); err != nil {
+		return nil, fmt.Errorf("parse seed file [%s] failed: %w", path, err)
+	}
+	return seed, nil
+}
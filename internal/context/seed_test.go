@@ -0,0 +1,112 @@
+package context_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+func Test_LoadSeed(t *testing.T) {
+	testCases := []struct {
+		name          string
+		seed          *factory.Seed
+		expectedError string
+		expectedData  map[string]string
+	}{
+		{
+			name:         "No Seed",
+			seed:         nil,
+			expectedData: map[string]string{"Anya": "Forger"},
+		},
+		{
+			name: "Inline And File",
+			seed: &factory.Seed{
+				Characters: []factory.SeedCharacter{{FirstName: "Donovan", LastName: "Desmond"}},
+				File:       "testdata/seed.json",
+			},
+			expectedData: map[string]string{
+				"Anya":     "Forger",
+				"Donovan":  "Desmond",
+				"Twilight": "Forger",
+				"Thorn":    "Princess",
+			},
+		},
+		{
+			name: "Duplicate Of Existing Character",
+			seed: &factory.Seed{
+				Characters: []factory.SeedCharacter{{FirstName: "Anya", LastName: "Desmond"}},
+			},
+			expectedError: "seed character #1: duplicate character [Anya]",
+		},
+		{
+			name: "Duplicate Between Inline And File",
+			seed: &factory.Seed{
+				Characters: []factory.SeedCharacter{{FirstName: "Thorn", LastName: "Briar"}},
+				File:       "testdata/seed.json",
+			},
+			expectedError: "seed character #3: duplicate character [Thorn]",
+		},
+		{
+			name: "Missing Last Name",
+			seed: &factory.Seed{
+				Characters: []factory.SeedCharacter{{FirstName: "Bond"}},
+			},
+			expectedError: "seed character #1: firstName and lastName are required",
+		},
+		{
+			name:          "Missing File",
+			seed:          &factory.Seed{File: "testdata/missing.json"},
+			expectedError: "read seed file failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			nfCtx := &nf_context.NFContext{
+				SpyFamilyData: map[string]string{"Anya": "Forger"},
+			}
+
+			err := nfCtx.LoadSeed(tc.seed)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				if len(nfCtx.SpyFamilyData) != 1 {
+					t.Errorf("Expected nothing to be loaded on error, got %v", nfCtx.SpyFamilyData)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %s", err)
+			}
+			if len(nfCtx.SpyFamilyData) != len(tc.expectedData) {
+				t.Errorf("Expected data %v, got %v", tc.expectedData, nfCtx.SpyFamilyData)
+			}
+			for firstName, lastName := range tc.expectedData {
+				if nfCtx.SpyFamilyData[firstName] != lastName {
+					t.Errorf("Expected %s %s, got %s", firstName, lastName, nfCtx.SpyFamilyData[firstName])
+				}
+			}
+		})
+	}
+}
+
+func Test_LoadSeedServed(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+	if err := server.Context.LoadSeed(&factory.Seed{File: "testdata/seed.json"}); err != nil {
+		t.Fatalf("Failed to load seed: %s", err)
+	}
+
+	status, body := server.Do(t, http.MethodGet, "/spyfamily/character/Twilight", nil)
+	if status != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	if string(body) != "Character: Twilight Forger" {
+		t.Errorf("Expected body Character: Twilight Forger, got %s", body)
+	}
+}
@@ -0,0 +1,73 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/google/uuid"
+)
+
+// state is what the NF keeps on disk between restarts.
+type state struct {
+	NfInstanceId string `json:"nfInstanceId"`
+}
+
+// loadNfId returns the NF instance ID stored in the state file, creating the file on first boot.
+// A missing or corrupt file gets a new ID. Without a state file every start gets a new ID.
+func loadNfId(path string) string {
+	if path == "" {
+		return uuid.New().String()
+	}
+
+	if nfId, err := readState(path); err == nil {
+		logger.CtxLog.Infof("NF instance ID %s loaded from %s", nfId, path)
+		return nfId
+	} else if !os.IsNotExist(err) {
+		logger.CtxLog.Warnf("State file %s is unusable, a new NF instance ID is generated: %+v", path, err)
+	}
+
+	nfId := uuid.New().String()
+	if err := writeState(path, state{NfInstanceId: nfId}); err != nil {
+		logger.CtxLog.Errorf("Save NF instance ID failed, it will change on restart: %+v", err)
+	}
+	return nfId
+}
+
+func readState(path string) (string, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	var s state
+	if err = json.Unmarshal(content, &s); err != nil {
+		return "", fmt.Errorf("decode state failed: %w", err)
+	}
+	if _, err = uuid.Parse(s.NfInstanceId); err != nil {
+		return "", fmt.Errorf("invalid nfInstanceId [%s]: %w", s.NfInstanceId, err)
+	}
+	return s.NfInstanceId, nil
+}
+
+// writeState replaces the state file atomically so a crash never leaves it half written.
+func writeState(path string, s state) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state failed: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("make state directory failed: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, append(content, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write state failed: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace state failed: %w", err)
+	}
+	return nil
+}
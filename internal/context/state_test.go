@@ -0,0 +1,53 @@
+package context_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/google/uuid"
+)
+
+func bootNfId(t *testing.T, stateFile string) string {
+	t.Helper()
+
+	cfg := testutil.DefaultConfig()
+	cfg.Configuration.StateFile = stateFile
+	nfId := nf_context.NewNfContext(cfg).NfId
+	if _, err := uuid.Parse(nfId); err != nil {
+		t.Fatalf("Expected NF instance ID to be a UUID, got %s", nfId)
+	}
+	return nfId
+}
+
+func Test_NfIdPersistence(t *testing.T) {
+	t.Run("Stable Across Restarts", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state", "nf.json")
+
+		first := bootNfId(t, stateFile)
+		second := bootNfId(t, stateFile)
+		if first != second {
+			t.Errorf("Expected the same NF instance ID after restart, got %s and %s", first, second)
+		}
+	})
+
+	t.Run("Corrupt File Is Regenerated", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "nf.json")
+		if err := os.WriteFile(stateFile, []byte(`{"nfInstanceId": "not-a-uuid"}`), 0o600); err != nil {
+			t.Fatalf("Failed to write state file: %s", err)
+		}
+
+		regenerated := bootNfId(t, stateFile)
+		if reloaded := bootNfId(t, stateFile); reloaded != regenerated {
+			t.Errorf("Expected regenerated NF instance ID %s to be saved, got %s", regenerated, reloaded)
+		}
+	})
+
+	t.Run("Without State File", func(t *testing.T) {
+		if first, second := bootNfId(t, ""), bootNfId(t, ""); first == second {
+			t.Errorf("Expected a new NF instance ID on every start, got %s twice", first)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/sirupsen/logrus"
+)
+
+type Policy string
+
+const (
+	// PolicyRequired dependencies must be reachable for the NF to start.
+	PolicyRequired Policy = "required"
+	// PolicyOptional dependencies may be down at startup. The NF starts degraded and keeps probing them.
+	PolicyOptional Policy = "optional"
+)
+
+const (
+	DefaultProbeTimeout  = 2 * time.Second
+	DefaultRetryInterval = 10 * time.Second
+)
+
+// Probe reports whether a dependency is reachable.
+type Probe func(ctx context.Context) error
+
+type Dependency struct {
+	Name    string
+	Policy  Policy
+	Timeout time.Duration
+	Probe   Probe
+}
+
+type Result struct {
+	Name      string    `json:"name"`
+	Policy    Policy    `json:"policy"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	LatencyMs float64   `json:"latencyMs"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Checker probes the NF's dependencies at startup and keeps retrying the optional ones that failed.
+// A nil Checker has no dependencies.
+type Checker struct {
+	RetryInterval time.Duration
+
+	dependencies []Dependency
+	mu           sync.RWMutex
+	results      map[string]Result
+	retrying     sync.WaitGroup
+}
+
+func NewChecker(dependencies []Dependency) *Checker {
+	return &Checker{
+		RetryInterval: DefaultRetryInterval,
+		dependencies:  dependencies,
+		results:       make(map[string]Result),
+	}
+}
+
+// TCPProbe succeeds when a TCP connection to address can be opened.
+func TCPProbe(address string) Probe {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// FromConfig lists the configured dependencies, plus the tracing collector as an optional one when tracing is on.
+func FromConfig(cfg *factory.Config) []Dependency {
+	dependencies := []Dependency{}
+	for _, dep := range cfg.GetDependencies() {
+		timeout := DefaultProbeTimeout
+		if dep.Timeout > 0 {
+			timeout = time.Duration(dep.Timeout) * time.Millisecond
+		}
+		dependencies = append(dependencies, Dependency{
+			Name:    dep.Name,
+			Policy:  Policy(dep.Policy),
+			Timeout: timeout,
+			Probe:   TCPProbe(dep.Address),
+		})
+	}
+
+	if tracing := cfg.GetTracing(); tracing.Enable {
+		dependencies = append(dependencies, Dependency{
+			Name:    "tracing-collector",
+			Policy:  PolicyOptional,
+			Timeout: DefaultProbeTimeout,
+			Probe:   TCPProbe(tracing.Endpoint),
+		})
+	}
+	return dependencies
+}
+
+// Startup probes every dependency once. It fails if a required dependency is unreachable;
+// unreachable optional dependencies are retried in the background until they answer or ctx is done.
+func (c *Checker) Startup(ctx context.Context) error {
+	var failed []Dependency
+	for _, dep := range c.dependencies {
+		if result := c.check(ctx, dep); !result.Healthy {
+			if dep.Policy == PolicyRequired {
+				return fmt.Errorf("required dependency [%s] is unreachable: %s", dep.Name, result.Error)
+			}
+			failed = append(failed, dep)
+		}
+	}
+
+	for _, dep := range failed {
+		logger.InitLog.Warnf("Starting degraded without optional dependency [%s], retry every %s", dep.Name, c.RetryInterval)
+		c.retrying.Add(1)
+		go c.retry(ctx, dep)
+	}
+	return nil
+}
+
+// Wait blocks until all background retries have stopped.
+func (c *Checker) Wait() {
+	if c == nil {
+		return
+	}
+	c.retrying.Wait()
+}
+
+func (c *Checker) retry(ctx context.Context, dep Dependency) {
+	defer c.retrying.Done()
+
+	ticker := time.NewTicker(c.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.check(ctx, dep).Healthy {
+				logger.InitLog.Infof("Optional dependency [%s] is reachable again", dep.Name)
+				return
+			}
+		}
+	}
+}
+
+func (c *Checker) check(ctx context.Context, dep Dependency) Result {
+	probeCtx, cancel := context.WithTimeout(ctx, dep.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dep.Probe(probeCtx)
+	latency := time.Since(start)
+	result := Result{
+		Name:      dep.Name,
+		Policy:    dep.Policy,
+		Healthy:   err == nil,
+		LatencyMs: float64(latency) / float64(time.Millisecond),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	c.mu.Lock()
+	c.results[dep.Name] = result
+	c.mu.Unlock()
+
+	log := logger.InitLog.WithFields(logrus.Fields{
+		"dependency": result.Name,
+		"policy":     result.Policy,
+		"healthy":    result.Healthy,
+		"latency":    latency,
+	})
+	if err != nil {
+		log.Warnf("Dependency check failed: %+v", err)
+	} else {
+		log.Info("Dependency check passed")
+	}
+	return result
+}
+
+// Results returns the latest result of every probed dependency, sorted by name.
+func (c *Checker) Results() []Result {
+	if c == nil {
+		return []Result{}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make([]Result, 0, len(c.results))
+	for _, result := range c.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// Degraded returns the names of the unreachable optional dependencies.
+func (c *Checker) Degraded() []string {
+	degraded := []string{}
+	for _, result := range c.Results() {
+		if !result.Healthy && result.Policy == PolicyOptional {
+			degraded = append(degraded, result.Name)
+		}
+	}
+	return degraded
+}
+
+// Ready reports whether every required dependency answered its latest probe.
+func (c *Checker) Ready() bool {
+	for _, result := range c.Results() {
+		if !result.Healthy && result.Policy == PolicyRequired {
+			return false
+		}
+	}
+	return true
+}
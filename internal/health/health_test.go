@@ -0,0 +1,99 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/health"
+)
+
+// unreachableAddress returns an address nothing listens on.
+func unreachableAddress(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err)
+	}
+	address := listener.Addr().String()
+	if err = listener.Close(); err != nil {
+		t.Fatalf("Failed to close listener: %s", err)
+	}
+	return address
+}
+
+func Test_CheckerStartup(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %s", err)
+	}
+	defer func() {
+		if closeErr := listener.Close(); closeErr != nil {
+			t.Errorf("Failed to close listener: %s", closeErr)
+		}
+	}()
+
+	t.Run("Optional Dependency Unreachable", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		checker := health.NewChecker([]health.Dependency{
+			{Name: "nrf", Policy: health.PolicyRequired, Timeout: time.Second, Probe: health.TCPProbe(listener.Addr().String())},
+			{Name: "webhook", Policy: health.PolicyOptional, Timeout: time.Second, Probe: health.TCPProbe(unreachableAddress(t))},
+		})
+
+		if err := checker.Startup(ctx); err != nil {
+			t.Fatalf("Expected startup to succeed degraded, got %s", err)
+		}
+		if degraded := checker.Degraded(); !slices.Equal(degraded, []string{"webhook"}) {
+			t.Errorf("Expected degraded [webhook], got %v", degraded)
+		}
+		if !checker.Ready() {
+			t.Errorf("Expected checker to be ready")
+		}
+
+		cancel()
+		checker.Wait()
+	})
+
+	t.Run("Required Dependency Unreachable", func(t *testing.T) {
+		checker := health.NewChecker([]health.Dependency{
+			{Name: "persistence", Policy: health.PolicyRequired, Timeout: time.Second, Probe: health.TCPProbe(unreachableAddress(t))},
+		})
+
+		err := checker.Startup(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "required dependency [persistence] is unreachable") {
+			t.Errorf("Expected required dependency error, got %v", err)
+		}
+	})
+
+	t.Run("Optional Dependency Recovers", func(t *testing.T) {
+		var up atomic.Bool
+		checker := health.NewChecker([]health.Dependency{{
+			Name:    "webhook",
+			Policy:  health.PolicyOptional,
+			Timeout: time.Second,
+			Probe: func(ctx context.Context) error {
+				if !up.Load() {
+					return errors.New("connection refused")
+				}
+				return nil
+			},
+		}})
+		checker.RetryInterval = 10 * time.Millisecond
+
+		if err := checker.Startup(context.Background()); err != nil {
+			t.Fatalf("Expected startup to succeed degraded, got %s", err)
+		}
+		up.Store(true)
+		checker.Wait()
+
+		if degraded := checker.Degraded(); len(degraded) != 0 {
+			t.Errorf("Expected no degraded dependency after recovery, got %v", degraded)
+		}
+	})
+}
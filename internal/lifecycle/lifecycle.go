@@ -0,0 +1,118 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+)
+
+const DefaultStopTimeout = 5 * time.Second
+
+// Hook is a background component started with the app and stopped on shutdown.
+// Hooks start by ascending Order and stop in the reverse order. Start and Stop are optional.
+type Hook struct {
+	Name        string
+	Order       int
+	Start       func(ctx context.Context) error
+	Stop        func(ctx context.Context) error
+	StopTimeout time.Duration
+}
+
+type Registry struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started []Hook
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(hook Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hook.StopTimeout == 0 {
+		hook.StopTimeout = DefaultStopTimeout
+	}
+	r.hooks = append(r.hooks, hook)
+}
+
+// Start runs every start hook in order. If one fails, the hooks already started are stopped again.
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	hooks := make([]Hook, len(r.hooks))
+	copy(hooks, r.hooks)
+	r.mu.Unlock()
+
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Order < hooks[j].Order
+	})
+
+	for _, hook := range hooks {
+		if hook.Start != nil {
+			if err := hook.Start(ctx); err != nil {
+				stopErr := r.Stop(context.Background())
+				return errors.Join(fmt.Errorf("start [%s] failed: %w", hook.Name, err), stopErr)
+			}
+		}
+		logger.MainLog.Debugf("Lifecycle hook [%s] started", hook.Name)
+
+		r.mu.Lock()
+		r.started = append(r.started, hook)
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop runs the stop hooks of the started components in reverse order. A hook that times out or
+// panics is logged and skipped so the remaining hooks still stop.
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	started := r.started
+	r.started = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := stopHook(ctx, started[i]); err != nil {
+			logger.MainLog.Errorf("Lifecycle hook failed: %+v", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func stopHook(ctx context.Context, hook Hook) error {
+	if hook.Stop == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hook.StopTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("stop [%s] panicked: %v", hook.Name, p)
+			}
+		}()
+		done <- hook.Stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		logger.MainLog.Debugf("Lifecycle hook [%s] stopped in %s", hook.Name, time.Since(start))
+		return err
+	case <-ctx.Done():
+		logger.MainLog.Warnf("Lifecycle hook [%s] is slow: not stopped after %s", hook.Name, hook.StopTimeout)
+		return fmt.Errorf("stop [%s] timed out after %s", hook.Name, hook.StopTimeout)
+	}
+}
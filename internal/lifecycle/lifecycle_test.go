@@ -0,0 +1,122 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/lifecycle"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) hook(name string, order int) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name:  name,
+		Order: order,
+		Start: func(context.Context) error {
+			r.record("start " + name)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			r.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func Test_Registry(t *testing.T) {
+	t.Run("Start In Order And Stop In Reverse", func(t *testing.T) {
+		rec := &recorder{}
+		registry := lifecycle.NewRegistry()
+		registry.Register(rec.hook("sbi", 2))
+		registry.Register(rec.hook("tracing", 0))
+		registry.Register(rec.hook("health", 1))
+
+		if err := registry.Start(context.Background()); err != nil {
+			t.Fatalf("Failed to start: %s", err)
+		}
+		if err := registry.Stop(context.Background()); err != nil {
+			t.Fatalf("Failed to stop: %s", err)
+		}
+
+		expected := []string{"start tracing", "start health", "start sbi", "stop sbi", "stop health", "stop tracing"}
+		if !slices.Equal(rec.events, expected) {
+			t.Errorf("Expected events %v, got %v", expected, rec.events)
+		}
+	})
+
+	t.Run("Failed Start Stops Started Hooks", func(t *testing.T) {
+		rec := &recorder{}
+		registry := lifecycle.NewRegistry()
+		registry.Register(rec.hook("tracing", 0))
+		registry.Register(lifecycle.Hook{
+			Name:  "broken",
+			Order: 1,
+			Start: func(context.Context) error { return errors.New("port in use") },
+		})
+		registry.Register(rec.hook("sbi", 2))
+
+		err := registry.Start(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "start [broken] failed: port in use") {
+			t.Errorf("Expected start error, got %v", err)
+		}
+
+		expected := []string{"start tracing", "stop tracing"}
+		if !slices.Equal(rec.events, expected) {
+			t.Errorf("Expected events %v, got %v", expected, rec.events)
+		}
+	})
+
+	t.Run("Slow And Panicking Hooks Do Not Block Others", func(t *testing.T) {
+		rec := &recorder{}
+		release := make(chan struct{})
+		defer close(release)
+
+		registry := lifecycle.NewRegistry()
+		registry.Register(rec.hook("tracing", 0))
+		registry.Register(lifecycle.Hook{
+			Name:        "slow",
+			Order:       1,
+			StopTimeout: 20 * time.Millisecond,
+			Stop: func(context.Context) error {
+				<-release
+				return nil
+			},
+		})
+		registry.Register(lifecycle.Hook{
+			Name:  "panicking",
+			Order: 2,
+			Stop:  func(context.Context) error { panic("boom") },
+		})
+		registry.Register(rec.hook("sbi", 3))
+
+		if err := registry.Start(context.Background()); err != nil {
+			t.Fatalf("Failed to start: %s", err)
+		}
+		err := registry.Stop(context.Background())
+		for _, expected := range []string{"stop [slow] timed out", "stop [panicking] panicked: boom"} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Expected error containing %q, got %v", expected, err)
+			}
+		}
+
+		expected := []string{"start tracing", "start sbi", "stop sbi", "stop tracing"}
+		if !slices.Equal(rec.events, expected) {
+			t.Errorf("Expected events %v, got %v", expected, rec.events)
+		}
+	})
+}
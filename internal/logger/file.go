@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	logger_util "github.com/free5gc/util/logger"
+)
+
+// RotatingFile is an append-only log file that is rotated once it would grow past MaxSize bytes.
+// Rotated files are renamed to <path>.1, <path>.2, ... with at most MaxBackups of them kept.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingFile opens (or creates) the log file. A maxSize of zero disables rotation.
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("make log directory failed: %w", err)
+	}
+
+	r := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(filepath.Clean(r.path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open log file failed: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		if closeErr := file.Close(); closeErr != nil {
+			return fmt.Errorf("stat log file failed: %w (close: %v)", err, closeErr)
+		}
+		return fmt.Errorf("stat log file failed: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("close log file failed: %w", err)
+	}
+
+	if r.maxBackups <= 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove log file failed: %w", err)
+		}
+		return r.open()
+	}
+
+	oldest := fmt.Sprintf("%s.%d", r.path, r.maxBackups)
+	if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove log backup failed: %w", err)
+	}
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", r.path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", r.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("shift log backup failed: %w", err)
+		}
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("rotate log file failed: %w", err)
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+type fileHook struct {
+	writer    *RotatingFile
+	formatter logrus.Formatter
+}
+
+func (h *fileHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *fileHook) Fire(entry *logrus.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return fmt.Errorf("format log entry failed: %w", err)
+	}
+	_, err = h.writer.Write(line)
+	return err
+}
+
+// AddFileOutput writes every module's log entries, as plain text, to a rotating file.
+// The returned function stops writing to it; the file must be closed separately.
+func AddFileOutput(path string, maxSize int64, maxBackups int) (*RotatingFile, func(), error) {
+	writer, err := NewRotatingFile(path, maxSize, maxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hook := &fileHook{
+		writer: writer,
+		formatter: &logrus.TextFormatter{
+			DisableColors:   true,
+			ForceQuote:      true,
+			TimestampFormat: logger_util.RFC3339Nano,
+		},
+	}
+	AddHook(hook)
+	return writer, func() { RemoveHook(hook) }, nil
+}
@@ -1,11 +1,27 @@
 package logger
 
 import (
+	"fmt"
+	"io"
+	"sync"
+
 	"github.com/sirupsen/logrus"
 
 	logger_util "github.com/free5gc/util/logger"
 )
 
+// Module names accepted by SetModuleLevel and by the logger.modules configuration.
+const (
+	ModuleMain      = "main"
+	ModuleInit      = "init"
+	ModuleConfig    = "config"
+	ModuleContext   = "context"
+	ModuleGin       = "gin"
+	ModuleSBI       = "sbi"
+	ModuleProcessor = "processor"
+	ModuleConsumer  = "consumer"
+)
+
 var (
 	Log     *logrus.Logger
 	NfLog   *logrus.Entry
@@ -14,8 +30,21 @@ var (
 	CfgLog  *logrus.Entry
 	CtxLog  *logrus.Entry
 
-	GinLog *logrus.Entry
-	SBILog *logrus.Entry
+	GinLog      *logrus.Entry
+	SBILog      *logrus.Entry
+	ProcLog     *logrus.Entry
+	ConsumerLog *logrus.Entry
+)
+
+type moduleLogger struct {
+	logger *logrus.Logger
+	// levelSet marks a level configured for this module only, which SetLevel must not override.
+	levelSet bool
+}
+
+var (
+	modulesMu sync.RWMutex
+	modules   = map[string]*moduleLogger{}
 )
 
 func init() {
@@ -26,11 +55,150 @@ func init() {
 	Log = logger_util.New(fieldsOrder)
 	NfLog = Log.WithField(logger_util.FieldNF, "ANYA")
 
-	MainLog = NfLog.WithField(logger_util.FieldCategory, "Main")
-	InitLog = NfLog.WithField(logger_util.FieldCategory, "Init")
-	CfgLog = NfLog.WithField(logger_util.FieldCategory, "CFG")
-	CtxLog = NfLog.WithField(logger_util.FieldCategory, "CTX")
+	MainLog = newModuleLog(ModuleMain, "Main")
+	InitLog = newModuleLog(ModuleInit, "Init")
+	CfgLog = newModuleLog(ModuleConfig, "CFG")
+	CtxLog = newModuleLog(ModuleContext, "CTX")
+
+	GinLog = newModuleLog(ModuleGin, "GIN")
+	SBILog = newModuleLog(ModuleSBI, "SBI")
+	ProcLog = newModuleLog(ModuleProcessor, "PROC")
+	ConsumerLog = newModuleLog(ModuleConsumer, "Consumer")
+}
+
+// newModuleLog creates a logger for one module sharing the root formatter and output,
+// so each module can be given its own level.
+func newModuleLog(module, category string) *logrus.Entry {
+	l := logrus.New()
+	l.SetFormatter(Log.Formatter)
+	l.SetOutput(Log.Out)
+	l.SetLevel(Log.GetLevel())
+	l.SetReportCaller(Log.ReportCaller)
+
+	modulesMu.Lock()
+	modules[module] = &moduleLogger{logger: l}
+	modulesMu.Unlock()
+
+	return l.WithField(logger_util.FieldNF, "ANYA").WithField(logger_util.FieldCategory, category)
+}
+
+// Modules returns the names of all module loggers.
+func Modules() []string {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	return names
+}
+
+// SetOutput redirects the root and every module logger.
+func SetOutput(out io.Writer) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	Log.SetOutput(out)
+	for _, m := range modules {
+		m.logger.SetOutput(out)
+	}
+}
+
+// SetLevel sets the default level, applied to every module without a level of its own.
+func SetLevel(level logrus.Level) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	Log.SetLevel(level)
+	for _, m := range modules {
+		if !m.levelSet {
+			m.logger.SetLevel(level)
+		}
+	}
+}
 
-	GinLog = NfLog.WithField(logger_util.FieldCategory, "GIN")
-	SBILog = NfLog.WithField(logger_util.FieldCategory, "SBI")
+// SetModuleLevel sets the level of a single module, overriding the default level.
+func SetModuleLevel(module string, level logrus.Level) error {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	m, ok := modules[module]
+	if !ok {
+		return fmt.Errorf("unknown log module [%s]", module)
+	}
+	m.levelSet = true
+	m.logger.SetLevel(level)
+	return nil
+}
+
+// ResetModuleLevel drops the level of a single module, so it follows the default level again.
+func ResetModuleLevel(module string) error {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	m, ok := modules[module]
+	if !ok {
+		return fmt.Errorf("unknown log module [%s]", module)
+	}
+	m.levelSet = false
+	m.logger.SetLevel(Log.GetLevel())
+	return nil
+}
+
+// GetModuleLevel returns the effective level of a module.
+func GetModuleLevel(module string) (logrus.Level, error) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	m, ok := modules[module]
+	if !ok {
+		return 0, fmt.Errorf("unknown log module [%s]", module)
+	}
+	return m.logger.GetLevel(), nil
+}
+
+// SetReportCaller toggles caller reporting on the root and every module logger.
+func SetReportCaller(reportCaller bool) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	Log.SetReportCaller(reportCaller)
+	for _, m := range modules {
+		m.logger.SetReportCaller(reportCaller)
+	}
+}
+
+// AddHook attaches a hook to the root and every module logger.
+func AddHook(hook logrus.Hook) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	Log.AddHook(hook)
+	for _, m := range modules {
+		m.logger.AddHook(hook)
+	}
+}
+
+// RemoveHook detaches a hook added by AddHook from the root and every module logger.
+func RemoveHook(hook logrus.Hook) {
+	modulesMu.RLock()
+	defer modulesMu.RUnlock()
+
+	Log.ReplaceHooks(withoutHook(Log.Hooks, hook))
+	for _, m := range modules {
+		m.logger.ReplaceHooks(withoutHook(m.logger.Hooks, hook))
+	}
+}
+
+func withoutHook(hooks logrus.LevelHooks, hook logrus.Hook) logrus.LevelHooks {
+	kept := make(logrus.LevelHooks, len(hooks))
+	for level, levelHooks := range hooks {
+		for _, h := range levelHooks {
+			if h != hook {
+				kept[level] = append(kept[level], h)
+			}
+		}
+	}
+	return kept
 }
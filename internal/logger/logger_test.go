@@ -0,0 +1,148 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_SetModuleLevel(t *testing.T) {
+	output := &bytes.Buffer{}
+	originalOutput := logger.Log.Out
+	logger.SetOutput(output)
+	defer logger.SetOutput(originalOutput)
+
+	originalLevel := logger.Log.GetLevel()
+	logger.SetLevel(logrus.InfoLevel)
+	defer logger.SetLevel(originalLevel)
+
+	if err := logger.SetModuleLevel(logger.ModuleSBI, logrus.DebugLevel); err != nil {
+		t.Fatalf("Failed to set module level: %s", err)
+	}
+	if err := logger.SetModuleLevel(logger.ModuleProcessor, logrus.ErrorLevel); err != nil {
+		t.Fatalf("Failed to set module level: %s", err)
+	}
+	defer func() {
+		for _, module := range []string{logger.ModuleSBI, logger.ModuleProcessor} {
+			if err := logger.ResetModuleLevel(module); err != nil {
+				t.Errorf("Failed to reset module level: %s", err)
+			}
+		}
+	}()
+
+	logger.SBILog.Debug("sbi debug entry")
+	logger.ProcLog.Warn("processor warn entry")
+	logger.ProcLog.Error("processor error entry")
+	logger.CtxLog.Debug("context debug entry")
+	logger.CtxLog.Info("context info entry")
+
+	testCases := []struct {
+		entry    string
+		expected bool
+	}{
+		{"sbi debug entry", true},
+		{"processor warn entry", false},
+		{"processor error entry", true},
+		{"context debug entry", false},
+		{"context info entry", true},
+	}
+	for _, tc := range testCases {
+		if strings.Contains(output.String(), tc.entry) != tc.expected {
+			t.Errorf("Expected %q logged to be %v, got log: %s", tc.entry, tc.expected, output.String())
+		}
+	}
+
+	t.Run("Default Level Does Not Override Module Level", func(t *testing.T) {
+		logger.SetLevel(logrus.WarnLevel)
+		defer logger.SetLevel(logrus.InfoLevel)
+
+		if lvl, err := logger.GetModuleLevel(logger.ModuleSBI); err != nil || lvl != logrus.DebugLevel {
+			t.Errorf("Expected sbi level debug, got %s (%v)", lvl, err)
+		}
+		if lvl, err := logger.GetModuleLevel(logger.ModuleContext); err != nil || lvl != logrus.WarnLevel {
+			t.Errorf("Expected context level warn, got %s (%v)", lvl, err)
+		}
+	})
+
+	t.Run("Unknown Module", func(t *testing.T) {
+		if err := logger.SetModuleLevel("unknown", logrus.DebugLevel); err == nil {
+			t.Errorf("Expected error for unknown module")
+		}
+		if err := logger.ResetModuleLevel("unknown"); err == nil {
+			t.Errorf("Expected error for unknown module")
+		}
+	})
+}
+
+func Test_RotatingFile(t *testing.T) {
+	const MAX_SIZE = 256
+	const MAX_BACKUPS = 2
+
+	path := filepath.Join(t.TempDir(), "log", "nf.log")
+	file, err := logger.NewRotatingFile(path, MAX_SIZE, MAX_BACKUPS)
+	if err != nil {
+		t.Fatalf("Failed to create rotating file: %s", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			t.Errorf("Failed to close rotating file: %s", closeErr)
+		}
+	}()
+
+	line := []byte(strings.Repeat("x", 99) + "\n")
+	for i := 0; i < 20; i++ {
+		if _, err = file.Write(line); err != nil {
+			t.Fatalf("Failed to write: %s", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, statErr := os.Stat(name)
+		if statErr != nil {
+			t.Errorf("Expected %s to exist: %s", name, statErr)
+			continue
+		}
+		if info.Size() > MAX_SIZE {
+			t.Errorf("Expected %s to be at most %d bytes, got %d", name, MAX_SIZE, info.Size())
+		}
+	}
+
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("Expected at most %d backups to be kept", MAX_BACKUPS)
+	}
+}
+
+func Test_AddFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nf.log")
+	file, remove, err := logger.AddFileOutput(path, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to add file output: %s", err)
+	}
+	t.Cleanup(remove)
+
+	logger.CtxLog.Info("file entry from context")
+	logger.SBILog.Info("file entry from sbi")
+	remove()
+	logger.SBILog.Info("entry after removal")
+	if err = file.Close(); err != nil {
+		t.Fatalf("Failed to close file: %s", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %s", err)
+	}
+	for _, entry := range []string{"file entry from context", "file entry from sbi"} {
+		if !strings.Contains(string(content), entry) {
+			t.Errorf("Expected log file to contain %q, got %s", entry, content)
+		}
+	}
+	if strings.Contains(string(content), "entry after removal") {
+		t.Errorf("Expected no entries after the file output is removed, got %s", content)
+	}
+}
@@ -0,0 +1,7 @@
+package perf
+
+import "time"
+
+func (w *Window) SetClock(now func() time.Time) {
+	w.now = now
+}
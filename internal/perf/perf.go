@@ -0,0 +1,197 @@
+package perf
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWindow is how far back the statistics of GET /admin/perf reach.
+	DefaultWindow = 5 * time.Minute
+	defaultSlots  = 10
+
+	// Buckets grow by 4% from 1µs, so a quantile is within 2% of the observed value,
+	// up to about 10 minutes where the last bucket collects everything longer.
+	bucketMin    = time.Microsecond
+	bucketGrowth = 1.04
+	numBuckets   = 520
+)
+
+var logGrowth = math.Log(bucketGrowth)
+
+// Summary is the statistics of one operation over the window, with durations in milliseconds.
+type Summary struct {
+	Count uint64  `json:"count"`
+	P50Ms float64 `json:"p50Ms"`
+	P95Ms float64 `json:"p95Ms"`
+	P99Ms float64 `json:"p99Ms"`
+	MaxMs float64 `json:"maxMs"`
+}
+
+type slot struct {
+	epoch   int64
+	count   uint64
+	max     time.Duration
+	buckets [numBuckets]uint32
+}
+
+// Window is a histogram of the durations observed over a sliding time window. The window is split
+// into slots that expire one at a time, and each slot counts durations in log-scaled buckets,
+// so its memory is fixed however many durations are observed.
+type Window struct {
+	mu        sync.Mutex
+	slotWidth time.Duration
+	slots     []slot
+	now       func() time.Time
+}
+
+func NewWindow(window time.Duration, slots int) *Window {
+	return &Window{
+		slotWidth: window / time.Duration(slots),
+		slots:     make([]slot, slots),
+		now:       time.Now,
+	}
+}
+
+func (w *Window) epoch() int64 {
+	return w.now().UnixNano() / int64(w.slotWidth)
+}
+
+// Observe adds a duration to the current slot.
+func (w *Window) Observe(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	epoch := w.epoch()
+	s := &w.slots[epoch%int64(len(w.slots))]
+	if s.epoch != epoch {
+		*s = slot{epoch: epoch}
+	}
+	s.count++
+	s.buckets[bucketOf(d)]++
+	if d > s.max {
+		s.max = d
+	}
+}
+
+// Summary merges the slots still inside the window and reads the quantiles from them.
+func (w *Window) Summary() Summary {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var merged slot
+	oldest := w.epoch() - int64(len(w.slots))
+	for i := range w.slots {
+		s := &w.slots[i]
+		if s.count == 0 || s.epoch <= oldest {
+			continue
+		}
+		merged.count += s.count
+		if s.max > merged.max {
+			merged.max = s.max
+		}
+		for b, n := range s.buckets {
+			merged.buckets[b] += n
+		}
+	}
+
+	return Summary{
+		Count: merged.count,
+		P50Ms: Milliseconds(merged.quantile(0.50)),
+		P95Ms: Milliseconds(merged.quantile(0.95)),
+		P99Ms: Milliseconds(merged.quantile(0.99)),
+		MaxMs: Milliseconds(merged.max),
+	}
+}
+
+// Milliseconds converts a duration to fractional milliseconds, the unit durations are reported in.
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (s *slot) quantile(q float64) time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(s.count)))
+	var seen uint64
+	for b, n := range s.buckets {
+		seen += uint64(n)
+		if seen >= rank {
+			return min(bucketValue(b), s.max)
+		}
+	}
+	return s.max
+}
+
+func bucketOf(d time.Duration) int {
+	if d < bucketMin {
+		return 0
+	}
+	b := 1 + int(math.Log(float64(d)/float64(bucketMin))/logGrowth)
+	return min(b, numBuckets-1)
+}
+
+// bucketValue is the geometric middle of a bucket, which bounds the relative error on both sides.
+func bucketValue(b int) time.Duration {
+	if b == 0 {
+		return bucketMin
+	}
+	return time.Duration(float64(bucketMin) * math.Pow(bucketGrowth, float64(b-1)+0.5))
+}
+
+// Recorder keeps one Window per operation name. Names come from the instrumented code,
+// not from requests, so their number is fixed too.
+type Recorder struct {
+	mu         sync.RWMutex
+	window     time.Duration
+	operations map[string]*Window
+}
+
+func NewRecorder(window time.Duration) *Recorder {
+	return &Recorder{
+		window:     window,
+		operations: make(map[string]*Window),
+	}
+}
+
+// Window returns the length of time the statistics cover.
+func (r *Recorder) Window() time.Duration {
+	return r.window
+}
+
+// Start starts timing one call of an operation and returns the function that records it.
+func (r *Recorder) Start(operation string) func() {
+	start := time.Now()
+	return func() {
+		r.Observe(operation, time.Since(start))
+	}
+}
+
+func (r *Recorder) Observe(operation string, d time.Duration) {
+	r.mu.RLock()
+	w, ok := r.operations[operation]
+	r.mu.RUnlock()
+	if !ok {
+		r.mu.Lock()
+		if w, ok = r.operations[operation]; !ok {
+			w = NewWindow(r.window, defaultSlots)
+			r.operations[operation] = w
+		}
+		r.mu.Unlock()
+	}
+	w.Observe(d)
+}
+
+// Summaries returns the statistics of every operation observed so far.
+func (r *Recorder) Summaries() map[string]Summary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := make(map[string]Summary, len(r.operations))
+	for name, w := range r.operations {
+		summaries[name] = w.Summary()
+	}
+	return summaries
+}
@@ -0,0 +1,89 @@
+package perf_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/perf"
+)
+
+func Test_WindowQuantiles(t *testing.T) {
+	const SAMPLES = 10000
+	const TOLERANCE = 0.03
+
+	w := perf.NewWindow(time.Minute, 10)
+	rng := rand.New(rand.NewSource(1))
+	durations := make([]time.Duration, SAMPLES)
+	for i := range durations {
+		// Log-normal around 2ms, with a tail into hundreds of milliseconds.
+		durations[i] = time.Duration(float64(2*time.Millisecond) * math.Exp(rng.NormFloat64()))
+		w.Observe(durations[i])
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	exact := func(q float64) time.Duration {
+		return durations[int(math.Ceil(q*SAMPLES))-1]
+	}
+
+	summary := w.Summary()
+	if summary.Count != SAMPLES {
+		t.Errorf("Expected count %d, got %d", SAMPLES, summary.Count)
+	}
+	if summary.MaxMs != perf.Milliseconds(durations[SAMPLES-1]) {
+		t.Errorf("Expected max %s, got %.3fms", durations[SAMPLES-1], summary.MaxMs)
+	}
+
+	testCases := []struct {
+		name       string
+		q          float64
+		observedMs float64
+	}{
+		{"p50", 0.50, summary.P50Ms},
+		{"p95", 0.95, summary.P95Ms},
+		{"p99", 0.99, summary.P99Ms},
+	}
+	for _, tc := range testCases {
+		expectedMs := perf.Milliseconds(exact(tc.q))
+		if relErr := math.Abs(tc.observedMs-expectedMs) / expectedMs; relErr > TOLERANCE {
+			t.Errorf("Expected %s within %.0f%% of %.3fms, got %.3fms", tc.name, TOLERANCE*100, expectedMs, tc.observedMs)
+		}
+	}
+}
+
+func Test_WindowSlides(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := perf.NewWindow(time.Minute, 6)
+	w.SetClock(func() time.Time { return now })
+
+	w.Observe(100 * time.Millisecond)
+	now = now.Add(30 * time.Second)
+	w.Observe(time.Millisecond)
+
+	if summary := w.Summary(); summary.Count != 2 || summary.MaxMs != 100 {
+		t.Errorf("Expected 2 durations with max 100ms, got %d with max %.3fms", summary.Count, summary.MaxMs)
+	}
+
+	now = now.Add(40 * time.Second)
+	if summary := w.Summary(); summary.Count != 1 || summary.MaxMs != 1 {
+		t.Errorf("Expected the 100ms duration to expire, got %d with max %.3fms", summary.Count, summary.MaxMs)
+	}
+
+	now = now.Add(time.Hour)
+	if summary := w.Summary(); summary.Count != 0 || summary.P99Ms != 0 {
+		t.Errorf("Expected an empty window, got %+v", summary)
+	}
+}
+
+func Test_RecorderAllocations(t *testing.T) {
+	r := perf.NewRecorder(time.Minute)
+	r.Observe("Warmup", time.Millisecond)
+
+	allocs := testing.AllocsPerRun(1000, func() {
+		r.Observe("Warmup", time.Millisecond)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected observing a known operation not to allocate, got %v", allocs)
+	}
+}
@@ -0,0 +1,80 @@
+package sbi
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+// AccessLog writes one entry per request. Successful requests are sampled, one in sampleRate, while
+// errors and requests slower than the threshold are always logged. Requests carrying X-Request-Id are
+// sampled by a hash of it, so retries of the same request get the same decision.
+type AccessLog struct {
+	log           *logrus.Entry
+	sampleRate    uint32
+	slowThreshold time.Duration
+
+	slow atomic.Int64
+}
+
+func NewAccessLog(log *logrus.Entry, cfg *factory.AccessLog) *AccessLog {
+	return &AccessLog{
+		log:           log,
+		sampleRate:    uint32(max(cfg.SampleRate, 1)),
+		slowThreshold: time.Duration(cfg.SlowThreshold) * time.Millisecond,
+	}
+}
+
+// Slow returns the number of requests slower than the threshold since the access log was created.
+func (a *AccessLog) Slow() int64 {
+	return a.slow.Load()
+}
+
+func (a *AccessLog) sampled(requestId string) bool {
+	if a.sampleRate == 1 {
+		return true
+	}
+	if requestId == "" {
+		return rand.Uint32N(a.sampleRate) == 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(requestId))
+	return h.Sum32()%a.sampleRate == 0
+}
+
+func (a *AccessLog) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path += "?" + raw
+		}
+
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		slow := a.slowThreshold > 0 && latency >= a.slowThreshold
+		if slow {
+			a.slow.Add(1)
+		}
+		if status < http.StatusBadRequest && !slow && !a.sampled(c.GetHeader(requestIdHeader)) {
+			return
+		}
+
+		entry := a.log.WithContext(c.Request.Context()).WithField("latency", latency)
+		if slow {
+			entry = entry.WithField("slow", true)
+		}
+		entry.Infof("| %3d | %15s | %-7s | %s | %s",
+			status, RealClientIP(c), c.Request.Method, path, c.Errors.ByType(gin.ErrorTypePrivate).String())
+	}
+}
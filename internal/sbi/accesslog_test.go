@@ -0,0 +1,115 @@
+package sbi_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func accessLogRouter(cfg *factory.AccessLog) (*gin.Engine, *sbi.AccessLog, *bytes.Buffer) {
+	gin.SetMode(gin.TestMode)
+
+	output := &bytes.Buffer{}
+	log := logrus.New()
+	log.SetOutput(output)
+	accessLog := sbi.NewAccessLog(logrus.NewEntry(log), cfg)
+
+	router := gin.New()
+	router.Use(accessLog.Middleware())
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	return router, accessLog, output
+}
+
+func serveAccessLog(router *gin.Engine, path, requestId string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if requestId != "" {
+		req.Header.Set("X-Request-Id", requestId)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func countLines(output *bytes.Buffer) int {
+	return strings.Count(output.String(), "\n")
+}
+
+func Test_AccessLog(t *testing.T) {
+	const SAMPLE_RATE = 10
+	const REQUESTS = 2000
+
+	t.Run("Sampling Ratio", func(t *testing.T) {
+		router, _, output := accessLogRouter(&factory.AccessLog{SampleRate: SAMPLE_RATE})
+		for i := 0; i < REQUESTS; i++ {
+			serveAccessLog(router, "/ok", fmt.Sprintf("request-%d", i))
+		}
+
+		logged := countLines(output)
+		expected := REQUESTS / SAMPLE_RATE
+		if logged < expected/2 || logged > expected*2 {
+			t.Errorf("Expected about %d of %d requests logged, got %d", expected, REQUESTS, logged)
+		}
+	})
+
+	t.Run("Same Request Id Same Decision", func(t *testing.T) {
+		router, _, output := accessLogRouter(&factory.AccessLog{SampleRate: SAMPLE_RATE})
+		for i := 0; i < 50; i++ {
+			output.Reset()
+			id := fmt.Sprintf("retry-%d", i)
+			serveAccessLog(router, "/ok", id)
+			first := countLines(output)
+			serveAccessLog(router, "/ok", id)
+			serveAccessLog(router, "/ok", id)
+			if countLines(output) != 3*first {
+				t.Errorf("Expected retries of %s to be sampled like the first attempt", id)
+			}
+		}
+	})
+
+	t.Run("Errors Always Logged", func(t *testing.T) {
+		router, _, output := accessLogRouter(&factory.AccessLog{SampleRate: REQUESTS})
+		for i := 0; i < 100; i++ {
+			serveAccessLog(router, "/fail", fmt.Sprintf("request-%d", i))
+		}
+		if logged := countLines(output); logged != 100 {
+			t.Errorf("Expected every error to be logged, got %d of 100", logged)
+		}
+	})
+
+	t.Run("Slow Requests", func(t *testing.T) {
+		router, accessLog, output := accessLogRouter(&factory.AccessLog{SampleRate: REQUESTS, SlowThreshold: 10})
+		serveAccessLog(router, "/slow", "slow-request")
+
+		if !strings.Contains(output.String(), "slow=true") {
+			t.Errorf("Expected slow request to be logged with slow=true, got %s", output.String())
+		}
+		if accessLog.Slow() != 1 {
+			t.Errorf("Expected 1 slow request, got %d", accessLog.Slow())
+		}
+	})
+
+	t.Run("Sampling Disabled", func(t *testing.T) {
+		router, accessLog, output := accessLogRouter(&factory.AccessLog{})
+		for i := 0; i < 10; i++ {
+			serveAccessLog(router, "/ok", "")
+		}
+		if logged := countLines(output); logged != 10 {
+			t.Errorf("Expected every request to be logged, got %d of 10", logged)
+		}
+		if strings.Contains(output.String(), "slow=true") || accessLog.Slow() != 0 {
+			t.Errorf("Expected no slow request without a threshold, got %s", output.String())
+		}
+	})
+}
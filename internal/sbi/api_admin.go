@@ -0,0 +1,200 @@
+package sbi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/perf"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/gin-gonic/gin"
+)
+
+// PerfResponse holds the timing statistics of each processor operation over the last Window.
+type PerfResponse struct {
+	Window     string                  `json:"window"`
+	Operations map[string]perf.Summary `json:"operations"`
+}
+
+type MaintenanceRequest struct {
+	Enabled    *bool  `json:"enabled" binding:"required"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retryAfter" binding:"omitempty,min=1"`
+}
+
+func (s *Server) getAdminRoute() []Route {
+	return []Route{
+		{
+			Name:    "Get Maintenance Mode",
+			Method:  http.MethodGet,
+			Pattern: "/maintenance",
+			APIFunc: s.HTTPGetMaintenance,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/admin/maintenance -w "\n"
+		},
+		{
+			Name:    "Set Maintenance Mode",
+			Method:  http.MethodPost,
+			Pattern: "/maintenance",
+			APIFunc: s.HTTPSetMaintenance,
+			// Use
+			// curl -X POST http://127.0.0.163:8000/admin/maintenance -w "\n" \
+			//   -H "Content-Type: application/json" -d '{"enabled": true, "message": "upgrading"}'
+		},
+		{
+			Name:    "Get Request Tap",
+			Method:  http.MethodGet,
+			Pattern: "/tap",
+			APIFunc: s.HTTPGetTap,
+			// Use
+			// curl -X GET "http://127.0.0.163:8000/admin/tap?path=/spyfamily&status=4xx" -w "\n"
+		},
+		{
+			Name:    "Clear Request Tap",
+			Method:  http.MethodDelete,
+			Pattern: "/tap",
+			APIFunc: s.HTTPClearTap,
+			// Use
+			// curl -X DELETE http://127.0.0.163:8000/admin/tap
+		},
+		{
+			Name:    "Drain",
+			Method:  http.MethodPost,
+			Pattern: "/drain",
+			APIFunc: s.HTTPDrain,
+			// Use
+			// curl -X POST http://127.0.0.163:8000/admin/drain -w "\n"
+		},
+		{
+			Name:    "Undrain",
+			Method:  http.MethodPost,
+			Pattern: "/undrain",
+			APIFunc: s.HTTPUndrain,
+			// Use
+			// curl -X POST http://127.0.0.163:8000/admin/undrain -w "\n"
+		},
+		{
+			Name:    "List Features",
+			Method:  http.MethodGet,
+			Pattern: "/features",
+			APIFunc: s.HTTPGetFeatures,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/admin/features -w "\n"
+		},
+		{
+			Name:    "Set Feature",
+			Method:  http.MethodPut,
+			Pattern: "/features/:Name",
+			APIFunc: s.HTTPSetFeature,
+			// Use
+			// curl -X PUT http://127.0.0.163:8000/admin/features/search -w "\n" \
+			//   -H "Content-Type: application/json" -d '{"enabled": true}'
+		},
+		{
+			Name:    "Get Processor Timings",
+			Method:  http.MethodGet,
+			Pattern: "/perf",
+			APIFunc: s.HTTPGetPerf,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/admin/perf -w "\n"
+		},
+		{
+			Name:    "List Routes",
+			Method:  http.MethodGet,
+			Pattern: "/routes",
+			APIFunc: s.HTTPGetRoutes,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/admin/routes -w "\n"
+		},
+	}
+}
+
+func (s *Server) HTTPGetRoutes(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetRoutes")
+
+	c.JSON(http.StatusOK, s.routes.Routes())
+}
+
+func (s *Server) HTTPGetPerf(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetPerf")
+
+	recorder := s.Processor().Perf()
+	c.JSON(http.StatusOK, PerfResponse{
+		Window:     recorder.Window().String(),
+		Operations: recorder.Summaries(),
+	})
+}
+
+func (s *Server) HTTPGetMaintenance(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetMaintenance")
+
+	s.Processor().GetMaintenance(c.Request.Context(), c)
+}
+
+func (s *Server) HTTPSetMaintenance(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPSetMaintenance")
+
+	var req MaintenanceRequest
+	if !s.bindStrict(c, &req) {
+		return
+	}
+
+	s.Processor().SetMaintenance(c.Request.Context(), c, nf_context.Maintenance{
+		Enabled:    *req.Enabled,
+		Message:    req.Message,
+		RetryAfter: req.RetryAfter,
+	})
+}
+
+func (s *Server) HTTPGetTap(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetTap")
+
+	if s.tap == nil {
+		processor.RespondError(c, processor.ErrTapDisabled, "Enable configuration.tap to capture requests")
+		return
+	}
+
+	statusClass := 0
+	if status := c.Query("status"); status != "" {
+		if len(status) != 3 || status[0] < '1' || status[0] > '5' || strings.ToLower(status[1:]) != "xx" {
+			processor.RespondError(c, processor.ErrInvalidQuery,
+				fmt.Sprintf("status [%s] is not a status class such as 2xx", status))
+			return
+		}
+		statusClass = int(status[0] - '0')
+	}
+
+	c.JSON(http.StatusOK, s.tap.Entries(c.Query("path"), statusClass))
+}
+
+func (s *Server) HTTPClearTap(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPClearTap")
+
+	if s.tap == nil {
+		processor.RespondError(c, processor.ErrTapDisabled, "Enable configuration.tap to capture requests")
+		return
+	}
+
+	s.tap.Clear()
+	c.Status(http.StatusNoContent)
+}
+
+// HTTPDrain starts draining and reports the requests and connections still open.
+func (s *Server) HTTPDrain(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPDrain")
+
+	s.drainer.Drain()
+	status := s.drainer.Status(s.limiter.Inflight())
+	logger.SBILog.Warnf("Draining, rejecting new requests after %vms (%d in flight)", status.GracePeriodMs, status.Inflight)
+	c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) HTTPUndrain(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPUndrain")
+
+	s.drainer.Undrain()
+	logger.SBILog.Warnf("Drain cancelled, accepting requests again")
+	c.JSON(http.StatusOK, s.drainer.Status(s.limiter.Inflight()))
+}
@@ -0,0 +1,169 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+)
+
+const labWritePath = "/lab/write"
+
+// addWriteRoute serves POST /lab/write on the server's own router, behind its middlewares, answering 201.
+func addWriteRoute(t *testing.T, server *testutil.TestServer) {
+	t.Helper()
+
+	registry := sbi.NewRouteRegistry()
+	err := registry.Register(sbi.NewModule("lab", "/lab", []sbi.Route{{
+		Name:    "Write",
+		Method:  http.MethodPost,
+		Pattern: "/write",
+		APIFunc: func(c *gin.Context) { c.Status(http.StatusCreated) },
+	}}))
+	if err != nil {
+		t.Fatalf("Failed to register module: %s", err)
+	}
+	registry.Apply(server.Router(), nil)
+}
+
+func Test_Maintenance(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+	addWriteRoute(t, server)
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodGet, "/admin/maintenance", nil)
+		if status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+
+		var maintenance nf_context.Maintenance
+		if err := json.Unmarshal(body, &maintenance); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if maintenance.Enabled {
+			t.Errorf("Expected maintenance to be disabled")
+		}
+	})
+
+	t.Run("Invalid Body", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodPost, "/admin/maintenance", strings.NewReader(`{"message": "x"}`))
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, status)
+		}
+
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Code != processor.ErrInvalidBody {
+			t.Errorf("Expected code %s, got %s", processor.ErrInvalidBody, problem.Code)
+		}
+	})
+
+	t.Run("Enable Rejects Writes", func(t *testing.T) {
+		status, _ := server.Do(t, http.MethodPost, "/admin/maintenance",
+			strings.NewReader(`{"enabled": true, "message": "upgrading", "retryAfter": 30}`))
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+
+		resp, body := server.DoResponse(t, http.MethodPost, labWritePath, nil)
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+		}
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "30" {
+			t.Errorf("Expected Retry-After 30, got %s", retryAfter)
+		}
+
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Detail != "upgrading" || problem.Cause != string(processor.ErrMaintenance) {
+			t.Errorf("Unexpected problem details: %+v", problem)
+		}
+	})
+
+	t.Run("Reads Keep Working", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodGet, "/spyfamily/character/Anya", nil)
+		if status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+		if string(body) != "Character: Anya Forger" {
+			t.Errorf("Expected body Character: Anya Forger, got %s", body)
+		}
+	})
+
+	t.Run("Disable Restores Writes", func(t *testing.T) {
+		status, _ := server.Do(t, http.MethodPost, "/admin/maintenance", strings.NewReader(`{"enabled": false}`))
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+
+		status, _ = server.Do(t, http.MethodPost, labWritePath, nil)
+		if status != http.StatusCreated {
+			t.Errorf("Expected status code %d, got %d", http.StatusCreated, status)
+		}
+	})
+}
+
+func Test_MaintenanceFromConfig(t *testing.T) {
+	cfg := testutil.DefaultConfig()
+	cfg.Configuration.Maintenance = &factory.Maintenance{
+		Enable:  true,
+		Message: "migrating",
+	}
+	server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+	addWriteRoute(t, server)
+
+	resp, _ := server.DoResponse(t, http.MethodPost, labWritePath, nil)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "120" {
+		t.Errorf("Expected default Retry-After 120, got %s", retryAfter)
+	}
+}
+
+func Test_Perf(t *testing.T) {
+	const REQUESTS = 5
+
+	server := testutil.NewTestServer(t, testutil.Options{})
+	for i := 0; i < REQUESTS; i++ {
+		if status, _ := server.Do(t, http.MethodGet, "/spyfamily/character/Anya", nil); status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+	}
+	server.Do(t, http.MethodGet, "/admin/maintenance", nil)
+
+	status, body := server.Do(t, http.MethodGet, "/admin/perf", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+
+	var perf sbi.PerfResponse
+	if err := json.Unmarshal(body, &perf); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if perf.Window != "5m0s" {
+		t.Errorf("Expected window 5m0s, got %s", perf.Window)
+	}
+
+	find := perf.Operations["FindSpyFamilyCharacterName"]
+	if find.Count != REQUESTS {
+		t.Errorf("Expected %d FindSpyFamilyCharacterName calls, got %d", REQUESTS, find.Count)
+	}
+	if find.P50Ms <= 0 || find.P50Ms > find.P99Ms || find.P99Ms > find.MaxMs {
+		t.Errorf("Expected 0 < p50 <= p99 <= max, got %+v", find)
+	}
+	if maintenance := perf.Operations["GetMaintenance"]; maintenance.Count != 1 {
+		t.Errorf("Expected 1 GetMaintenance call, got %d", maintenance.Count)
+	}
+}
@@ -0,0 +1,192 @@
+package sbi
+
+import (
+	"net/http"
+
+	"github.com/Alonza0314/nf-example/internal/health"
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsPath = "/metrics"
+	infoPath    = "/info"
+	readyPath   = "/ready"
+	healthPath  = "/health"
+)
+
+type InfoResponse struct {
+	NfInstanceId string       `json:"nfInstanceId"`
+	NfName       string       `json:"nfName"`
+	Version      string       `json:"version"`
+	Scheme       string       `json:"scheme"`
+	Requests     RequestsInfo `json:"requests"`
+}
+
+type ReadyResponse struct {
+	Ready        bool            `json:"ready"`
+	Draining     bool            `json:"draining,omitempty"`
+	Dependencies []health.Result `json:"dependencies"`
+}
+
+type HealthResponse struct {
+	Status       string          `json:"status"`
+	Degraded     []string        `json:"degraded,omitempty"`
+	Dependencies []health.Result `json:"dependencies,omitempty"`
+}
+
+type RequestsInfo struct {
+	Inflight int64 `json:"inflight"`
+	Queued   int64 `json:"queued"`
+	Rejected int64 `json:"rejected"`
+}
+
+func (s *Server) getObservabilityRoute() []Route {
+	return []Route{
+		{
+			Name:    "Metrics",
+			Method:  http.MethodGet,
+			Pattern: metricsPath,
+			APIFunc: s.HTTPGetMetrics,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/metrics
+		},
+		{
+			Name:    "Error Codes",
+			Method:  http.MethodGet,
+			Pattern: "/errors",
+			APIFunc: s.HTTPGetErrors,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/errors -w "\n"
+		},
+		{
+			Name:    "Readiness",
+			Method:  http.MethodGet,
+			Pattern: readyPath,
+			APIFunc: s.HTTPGetReady,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/ready -w "\n"
+		},
+		{
+			Name:    "Health",
+			Method:  http.MethodGet,
+			Pattern: healthPath,
+			APIFunc: s.HTTPGetHealth,
+			// Use
+			// curl -X GET "http://127.0.0.163:8000/health?verbose=true" -w "\n"
+		},
+		{
+			Name:    "Info",
+			Method:  http.MethodGet,
+			Pattern: infoPath,
+			APIFunc: s.HTTPGetInfo,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/info -w "\n"
+		},
+		{
+			Name:    "Capabilities",
+			Method:  http.MethodGet,
+			Pattern: "/capabilities",
+			APIFunc: s.HTTPGetCapabilities,
+			// Use
+			// curl -X GET http://127.0.0.163:8000/capabilities -w "\n"
+		},
+	}
+}
+
+// newRequestDuration creates the request latency histogram. Observations made inside a sampled trace
+// carry the trace ID as an exemplar.
+func newRequestDuration() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nf_http_request_duration_seconds",
+		Help:    "Time taken to process a request.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+}
+
+// newMetricsRegistry exposes the limiter and access log counters and the request latency histogram.
+func newMetricsRegistry(limiter *Limiter, accessLog *AccessLog, requestDuration *prometheus.HistogramVec) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		requestDuration,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "nf_http_requests_inflight",
+			Help: "Number of requests currently being processed.",
+		}, func() float64 { return float64(limiter.Inflight()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "nf_http_requests_queued",
+			Help: "Number of requests waiting for a free processing slot.",
+		}, func() float64 { return float64(limiter.Queued()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "nf_http_requests_rejected_total",
+			Help: "Number of requests rejected because the NF was saturated.",
+		}, func() float64 { return float64(limiter.Rejected()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "nf_http_requests_slow_total",
+			Help: "Number of requests slower than the access log slow threshold.",
+		}, func() float64 { return float64(accessLog.Slow()) }),
+	)
+	return registry
+}
+
+// HTTPGetMetrics serves the Prometheus text format, or OpenMetrics (which includes exemplars)
+// when the scraper asks for it in the Accept header.
+func (s *Server) HTTPGetMetrics(c *gin.Context) {
+	promhttp.HandlerFor(s.metrics, promhttp.HandlerOpts{EnableOpenMetrics: true}).ServeHTTP(c.Writer, c.Request)
+}
+
+func (s *Server) HTTPGetInfo(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetInfo")
+
+	c.JSON(http.StatusOK, InfoResponse{
+		NfInstanceId: s.Context().NfId,
+		NfName:       s.Config().Configuration.NfName,
+		Version:      nfVersion(),
+		Scheme:       string(s.Config().Configuration.Sbi.Scheme),
+		Requests: RequestsInfo{
+			Inflight: s.limiter.Inflight(),
+			Queued:   s.limiter.Queued(),
+			Rejected: s.limiter.Rejected(),
+		},
+	})
+}
+
+func (s *Server) HTTPGetErrors(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetErrors")
+
+	s.Processor().ListErrorCodes(c.Request.Context(), c)
+}
+
+func (s *Server) HTTPGetReady(c *gin.Context) {
+	checker := s.Context().Health
+
+	draining := s.drainer.Draining()
+	status := http.StatusOK
+	if !checker.Ready() || draining {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, ReadyResponse{
+		Ready:        status == http.StatusOK,
+		Draining:     draining,
+		Dependencies: checker.Results(),
+	})
+}
+
+// HTTPGetHealth reports "degraded" while optional dependencies are unreachable.
+// With verbose=true the degraded dependencies and every probe result are included.
+func (s *Server) HTTPGetHealth(c *gin.Context) {
+	checker := s.Context().Health
+
+	resp := HealthResponse{Status: "ok"}
+	degraded := checker.Degraded()
+	if len(degraded) > 0 {
+		resp.Status = "degraded"
+	}
+	if c.Query("verbose") == "true" {
+		resp.Degraded = degraded
+		resp.Dependencies = checker.Results()
+	}
+	c.JSON(http.StatusOK, resp)
+}
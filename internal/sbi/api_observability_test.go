@@ -0,0 +1,165 @@
+package sbi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/health"
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func Test_ObservabilityEndpoints(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	t.Run("Metrics", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodGet, "/metrics", nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+		for _, metric := range []string{
+			"nf_http_requests_inflight 0",
+			"nf_http_requests_queued 0",
+			"nf_http_requests_rejected_total 0",
+			"nf_http_requests_slow_total 0",
+		} {
+			if !strings.Contains(string(body), metric) {
+				t.Errorf("Expected metrics to contain %q, got %s", metric, body)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodGet, "/errors", nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+
+		var catalogue []processor.ErrorInfo
+		if err := json.Unmarshal(body, &catalogue); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if len(catalogue) != len(processor.ErrorCatalogue()) {
+			t.Errorf("Expected %d error codes, got %d", len(processor.ErrorCatalogue()), len(catalogue))
+		}
+	})
+
+	t.Run("Health", func(t *testing.T) {
+		server.Context.Health = health.NewChecker([]health.Dependency{{
+			Name:    "webhook",
+			Policy:  health.PolicyOptional,
+			Timeout: time.Second,
+			Probe:   func(ctx context.Context) error { return errors.New("connection refused") },
+		}})
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := server.Context.Health.Startup(ctx); err != nil {
+			t.Fatalf("Expected startup to succeed degraded, got %s", err)
+		}
+		defer func() {
+			cancel()
+			server.Context.Health.Wait()
+			server.Context.Health = nil
+		}()
+
+		status, body := server.Do(t, http.MethodGet, "/health?verbose=true", nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+		var resp sbi.HealthResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if resp.Status != "degraded" || len(resp.Degraded) != 1 || resp.Degraded[0] != "webhook" {
+			t.Errorf("Expected degraded webhook, got %+v", resp)
+		}
+
+		if status, _ = server.Do(t, http.MethodGet, "/ready", nil); status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+	})
+
+	t.Run("Info", func(t *testing.T) {
+		status, body := server.Do(t, http.MethodGet, "/info", nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+
+		var info sbi.InfoResponse
+		if err := json.Unmarshal(body, &info); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if info.NfInstanceId != server.Context.NfId {
+			t.Errorf("Expected nfInstanceId %s, got %s", server.Context.NfId, info.NfInstanceId)
+		}
+		if info.NfName != "ANYA" {
+			t.Errorf("Expected nfName ANYA, got %s", info.NfName)
+		}
+		if info.Requests.Inflight != 0 || info.Requests.Queued != 0 {
+			t.Errorf("Expected no requests in flight, got %+v", info.Requests)
+		}
+	})
+}
+
+func scrapeMetrics(t *testing.T, server *testutil.TestServer, accept string) (string, string) {
+	t.Helper()
+
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	resp, body := server.DoWithHeaders(t, http.MethodGet, "/metrics", nil, header)
+	return resp.Header.Get("Content-Type"), string(body)
+}
+
+func Test_MetricsExemplars(t *testing.T) {
+	const OPENMETRICS = "application/openmetrics-text; version=1.0.0"
+	const DURATION_BUCKET = `nf_http_request_duration_seconds_bucket{method="GET",route="/spyfamily/character/:Name",status="200"`
+
+	testCases := []struct {
+		name             string
+		tracing          bool
+		accept           string
+		expectedType     string
+		expectedExemplar bool
+	}{
+		{"Prometheus Without Tracing", false, "", "text/plain", false},
+		{"OpenMetrics Without Tracing", false, OPENMETRICS, "application/openmetrics-text", false},
+		{"Prometheus With Tracing", true, "", "text/plain", false},
+		{"OpenMetrics With Tracing", true, OPENMETRICS, "application/openmetrics-text", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.tracing {
+				otel.SetTracerProvider(sdktrace.NewTracerProvider())
+				// The global getter returns a delegate rather than the no-op provider, so restore a new no-op one.
+				defer otel.SetTracerProvider(noop.NewTracerProvider())
+			}
+
+			server := testutil.NewTestServer(t, testutil.Options{})
+			if status, _ := server.Do(t, http.MethodGet, "/spyfamily/character/Anya", nil); status != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+			}
+
+			contentType, body := scrapeMetrics(t, server, tc.accept)
+			if !strings.HasPrefix(contentType, tc.expectedType) {
+				t.Errorf("Expected content type %s, got %s", tc.expectedType, contentType)
+			}
+			if !strings.Contains(body, DURATION_BUCKET) {
+				t.Errorf("Expected metrics to contain %q, got %s", DURATION_BUCKET, body)
+			}
+			if hasExemplar := strings.Contains(body, `# {trace_id="`); hasExemplar != tc.expectedExemplar {
+				t.Errorf("Expected exemplar present to be %v, got %s", tc.expectedExemplar, body)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package sbi
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/timing"
 	"github.com/gin-gonic/gin"
+
+	logger_util "github.com/free5gc/util/logger"
 )
 
 func (s *Server) getSpyFamilyRoute() []Route {
@@ -28,17 +33,55 @@ func (s *Server) getSpyFamilyRoute() []Route {
 			// curl -X GET http://127.0.0.163:8000/spyfamily/Anya -w "\n"
 			// "Character: Anya Forger"
 		},
+		{
+			Name:    "SPYxFAMILY Search",
+			Method:  http.MethodGet,
+			Pattern: "/search",
+			APIFunc: s.HTTPSearchSpyFamilyCharacters,
+			Feature: "search",
+			// Use
+			// curl -X GET "http://127.0.0.163:8000/spyfamily/search?lastName=Forger" -w "\n"
+			// ["Anya","Bond","Loid","Yor"]
+		},
 	}
 }
 
 func (s *Server) HTTPSerchSpyFamilyCharacter(c *gin.Context) {
-	logger.SBILog.Infof("In HTTPSerchCharacter")
+	logger.SBILog.WithField(logger_util.FieldRemoteAddr, RealClientIP(c)).Infof("In HTTPSerchCharacter")
 
 	targetName := c.Param("Name")
 	if targetName == "" {
-		c.String(http.StatusBadRequest, "No name provided")
+		processor.RespondError(c, processor.ErrMissingParameter, "No name provided")
+		return
+	}
+	endBinding := timing.Start(c.Request.Context(), timing.PhaseBinding)
+	err := validatePathParam(targetName)
+	endBinding()
+	if err != nil {
+		processor.RespondError(c, processor.ErrInvalidParameter, fmt.Sprintf("name %s", err))
+		return
+	}
+
+	s.Processor().FindSpyFamilyCharacterName(c.Request.Context(), c, targetName)
+}
+
+// HTTPSearchSpyFamilyCharacters lists the characters with the lastName query parameter. It is served
+// only while the search feature is enabled.
+func (s *Server) HTTPSearchSpyFamilyCharacters(c *gin.Context) {
+	logger.SBILog.WithField(logger_util.FieldRemoteAddr, RealClientIP(c)).Infof("In HTTPSearchSpyFamilyCharacters")
+
+	lastName := c.Query("lastName")
+	if lastName == "" {
+		processor.RespondError(c, processor.ErrMissingParameter, "No lastName provided")
+		return
+	}
+	endBinding := timing.Start(c.Request.Context(), timing.PhaseBinding)
+	err := validatePathParam(lastName)
+	endBinding()
+	if err != nil {
+		processor.RespondError(c, processor.ErrInvalidParameter, fmt.Sprintf("lastName %s", err))
 		return
 	}
 
-	s.Processor().FindSpyFamilyCharacterName(c, targetName)
+	s.Processor().SearchSpyFamilyCharacters(c.Request.Context(), c, lastName)
 }
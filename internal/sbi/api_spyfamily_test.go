@@ -1,43 +1,25 @@
 package sbi_test
 
 import (
+	"encoding/json"
 	"net/http"
-	"net/http/httptest"
+	"slices"
 	"testing"
 
-	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
 	"github.com/Alonza0314/nf-example/pkg/factory"
-	"github.com/gin-gonic/gin"
-	"go.uber.org/mock/gomock"
 )
 
 func Test_HTTPSerchSpyFamilyCharacter(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-
-	mockCtrl := gomock.NewController(t)
-	nfApp := sbi.NewMocknfApp(mockCtrl)
-	nfApp.EXPECT().Config().Return(&factory.Config{
-		Configuration: &factory.Configuration{
-			Sbi: &factory.Sbi{
-				Port: 8000,
-			},
-		},
-	}).AnyTimes()
-	server := sbi.NewServer(nfApp, "")
+	server := testutil.NewTestServer(t, testutil.Options{})
 
 	t.Run("No name provided", func(t *testing.T) {
 		const EXPECTED_STATUS = http.StatusBadRequest
-		const EXPECTED_BODY = "No name provided"
+		const EXPECTED_DETAIL = "No name provided"
+		const EXPECTED_CODE = processor.ErrMissingParameter
 
-		httpRecorder := httptest.NewRecorder()
-		ginCtx, _ := gin.CreateTestContext(httpRecorder)
-
-		var err error
-		ginCtx.Request, err = http.NewRequest("GET", "/spyfamily", nil)
-		if err != nil {
-			t.Errorf("Failed to create request: %s", err)
-			return
-		}
+		httpRecorder, ginCtx := testutil.NewGinContext(t, http.MethodGet, "/spyfamily", nil)
 
 		server.HTTPSerchSpyFamilyCharacter(ginCtx)
 
@@ -45,8 +27,105 @@ func Test_HTTPSerchSpyFamilyCharacter(t *testing.T) {
 			t.Errorf("Expected status code %d, got %d", EXPECTED_STATUS, httpRecorder.Code)
 		}
 
-		if httpRecorder.Body.String() != EXPECTED_BODY {
-			t.Errorf("Expected body %s, got %s", EXPECTED_BODY, httpRecorder.Body.String())
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Code != EXPECTED_CODE {
+			t.Errorf("Expected code %s, got %s", EXPECTED_CODE, problem.Code)
+		}
+		if problem.Detail != EXPECTED_DETAIL {
+			t.Errorf("Expected detail %s, got %s", EXPECTED_DETAIL, problem.Detail)
 		}
 	})
 }
+
+func Test_CharacterPathEncoding(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+	err := server.Context.LoadSeed(&factory.Seed{Characters: []factory.SeedCharacter{
+		{FirstName: "AC/DC", LastName: "Rock"},
+		{FirstName: "Mary Jane", LastName: "Watson"},
+		{FirstName: "C++", LastName: "Stroustrup"},
+		{FirstName: "Cafe\u0301", LastName: "Noir"},
+		{FirstName: "安妮亞", LastName: "佛傑"},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to seed characters: %s", err)
+	}
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"Encoded Slash", "/spyfamily/character/AC%2FDC", http.StatusOK, "Character: AC/DC Rock"},
+		{"Encoded Space", "/spyfamily/character/Mary%20Jane", http.StatusOK, "Character: Mary Jane Watson"},
+		{"Plus Sign", "/spyfamily/character/C++", http.StatusOK, "Character: C++ Stroustrup"},
+		{"Encoded Plus Sign", "/spyfamily/character/C%2B%2B", http.StatusOK, "Character: C++ Stroustrup"},
+		{"Composed Unicode", "/spyfamily/character/Caf%C3%A9", http.StatusOK, "Character: Caf\u00e9 Noir"},
+		{"Decomposed Unicode", "/spyfamily/character/Cafe%CC%81", http.StatusOK, "Character: Cafe\u0301 Noir"},
+		{"CJK", "/spyfamily/character/%E5%AE%89%E5%A6%AE%E4%BA%9E", http.StatusOK, "Character: 安妮亞 佛傑"},
+		{"Encoded Newline", "/spyfamily/character/Anya%0A", http.StatusBadRequest, ""},
+		{"Encoded NUL", "/spyfamily/character/An%00ya", http.StatusBadRequest, ""},
+		{"Invalid UTF-8", "/spyfamily/character/%FF", http.StatusBadRequest, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := server.Do(t, http.MethodGet, tc.path, nil)
+			if status != tc.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tc.expectedStatus, status, body)
+			}
+			if tc.expectedStatus == http.StatusOK {
+				if string(body) != tc.expectedBody {
+					t.Errorf("Expected body %q, got %q", tc.expectedBody, body)
+				}
+				return
+			}
+
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Code != processor.ErrInvalidParameter {
+				t.Errorf("Expected code %s, got %s", processor.ErrInvalidParameter, problem.Code)
+			}
+		})
+	}
+}
+
+func Test_HTTPSearchSpyFamilyCharacters(t *testing.T) {
+	const SEARCH_PATH = "/spyfamily/search?lastName=Forger"
+
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	if status, _ := server.Do(t, http.MethodGet, SEARCH_PATH, nil); status != http.StatusNotFound {
+		t.Errorf("Expected status code %d while search is disabled, got %d", http.StatusNotFound, status)
+	}
+
+	if status, body := setFeature(t, server, "search", `{"enabled": true}`); status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, status, body)
+	}
+	status, body := server.Do(t, http.MethodGet, SEARCH_PATH, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	var firstNames []string
+	if err := json.Unmarshal(body, &firstNames); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if expected := []string{"Anya", "Bond", "Loid", "Yor"}; !slices.Equal(firstNames, expected) {
+		t.Errorf("Expected %v, got %v", expected, firstNames)
+	}
+
+	if status, _ = server.Do(t, http.MethodGet, "/spyfamily/search", nil); status != http.StatusBadRequest {
+		t.Errorf("Expected status code %d without lastName, got %d", http.StatusBadRequest, status)
+	}
+
+	if status, _ = setFeature(t, server, "search", `{"enabled": false}`); status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	if status, _ = server.Do(t, http.MethodGet, SEARCH_PATH, nil); status != http.StatusNotFound {
+		t.Errorf("Expected status code %d after disabling search, got %d", http.StatusNotFound, status)
+	}
+}
@@ -0,0 +1,123 @@
+package sbi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/timing"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/free5gc/openapi/models"
+)
+
+const unknownFieldPrefix = "json: unknown field "
+
+// bindStrict decodes the JSON body into obj and validates its binding tags. Unless strict decoding is
+// turned off in the configuration, unknown fields are rejected so typos are not silently ignored.
+// On failure a 400 naming the offending fields is sent and false is returned.
+func (s *Server) bindStrict(c *gin.Context, obj any) bool {
+	defer timing.Start(c.Request.Context(), timing.PhaseBinding)()
+
+	if !s.strictDecoding() {
+		if err := c.ShouldBindJSON(obj); err != nil {
+			processor.RespondInvalidParams(c, processor.ErrInvalidBody, err.Error(), invalidParams(obj, err))
+			return false
+		}
+		return true
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		processor.RespondError(c, processor.ErrInvalidBody, err.Error())
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(obj); err == nil {
+		err = binding.Validator.ValidateStruct(obj)
+	}
+	if err != nil {
+		processor.RespondInvalidParams(c, processor.ErrInvalidBody, err.Error(), invalidParams(obj, err))
+		return false
+	}
+	return true
+}
+
+func (s *Server) strictDecoding() bool {
+	strict := s.Config().Configuration.Sbi.StrictDecoding
+	return strict == nil || *strict
+}
+
+// invalidParams names the fields behind a decoding or validation error as JSON pointers.
+func invalidParams(obj any, err error) []models.InvalidParam {
+	if field, ok := strings.CutPrefix(err.Error(), unknownFieldPrefix); ok {
+		return []models.InvalidParam{{
+			Param:  "/" + strings.Trim(field, `"`),
+			Reason: "unknown field",
+		}}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		params := make([]models.InvalidParam, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			params = append(params, models.InvalidParam{
+				Param:  "/" + jsonFieldName(obj, fieldErr.StructField()),
+				Reason: fieldErr.Tag(),
+			})
+		}
+		return params
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []models.InvalidParam{{
+			Param:  "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+			Reason: "expected " + typeErr.Type.String(),
+		}}
+	}
+	return nil
+}
+
+// jsonFieldName returns the JSON name of a top-level field of obj, falling back to the Go name.
+func jsonFieldName(obj any, field string) string {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return field
+	}
+	structField, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	if name, _, _ := strings.Cut(structField.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field
+}
+
+// validatePathParam rejects a path parameter, already unescaped by the router, that is not valid
+// UTF-8 or that contains control characters such as an encoded newline or NUL.
+func validatePathParam(value string) error {
+	if !utf8.ValidString(value) {
+		return errors.New("is not valid UTF-8")
+	}
+	if i := strings.IndexFunc(value, unicode.IsControl); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(value[i:])
+		return fmt.Errorf("contains control character %U", r)
+	}
+	return nil
+}
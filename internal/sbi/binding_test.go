@@ -0,0 +1,78 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+)
+
+func Test_StrictDecoding(t *testing.T) {
+	testCases := []struct {
+		name           string
+		strict         *bool
+		body           string
+		expectedStatus int
+		expectedParam  string
+	}{
+		{
+			name:           "Valid Body",
+			body:           `{"enabled": false, "retryAfter": 30}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown Field",
+			body:           `{"enabled": false, "retyAfter": 30}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "/retyAfter",
+		},
+		{
+			name:           "Missing Required Field",
+			body:           `{"message": "upgrading"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "/enabled",
+		},
+		{
+			name:           "Wrong Type",
+			body:           `{"enabled": "yes"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedParam:  "/enabled",
+		},
+		{
+			name:           "Unknown Field Accepted When Not Strict",
+			strict:         new(bool),
+			body:           `{"enabled": false, "retyAfter": 30}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testutil.DefaultConfig()
+			cfg.Configuration.Sbi.StrictDecoding = tc.strict
+			server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+
+			status, body := server.Do(t, http.MethodPost, "/admin/maintenance", strings.NewReader(tc.body))
+			if status != tc.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tc.expectedStatus, status, body)
+			}
+			if tc.expectedParam == "" {
+				return
+			}
+
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Code != processor.ErrInvalidBody {
+				t.Errorf("Expected code %s, got %s", processor.ErrInvalidBody, problem.Code)
+			}
+			if len(problem.InvalidParams) != 1 || problem.InvalidParams[0].Param != tc.expectedParam {
+				t.Errorf("Expected invalid param %s, got %+v", tc.expectedParam, problem.InvalidParams)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package sbi
+
+import (
+	"net/http"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/gin-gonic/gin"
+)
+
+// CapabilitiesVersion is the version of the GET /capabilities document. It is bumped whenever a field
+// is removed or changes meaning; new fields are added without a bump.
+const CapabilitiesVersion = 1
+
+// Capabilities lets clients feature-detect this NF without reading its whole API description.
+type Capabilities struct {
+	Version   int              `json:"version"`
+	NfVersion string           `json:"nfVersion"`
+	Features  map[string]bool  `json:"features"`
+	Limits    CapabilityLimits `json:"limits"`
+	// StrictDecoding tells whether request bodies with unknown JSON fields are rejected.
+	StrictDecoding bool `json:"strictDecoding"`
+	// Languages lists the Accept-Language values error titles are translated to, besides English.
+	Languages []string `json:"languages"`
+	// Routes lists "METHOD /path" for every route currently served. Routes of disabled features are left out.
+	Routes []string `json:"routes"`
+}
+
+// CapabilityLimits holds the request limits of the NF. Zero means unlimited.
+type CapabilityLimits struct {
+	// MaxRequestTimeout caps the X-Request-Timeout header, in milliseconds.
+	MaxRequestTimeout int64 `json:"maxRequestTimeout"`
+	MaxInflight       int   `json:"maxInflight"`
+	MaxQueue          int   `json:"maxQueue"`
+}
+
+func (s *Server) capabilities() Capabilities {
+	features := map[string]bool{}
+	for _, info := range s.features() {
+		features[info.Name] = info.Enabled
+	}
+
+	routes := []string{}
+	for _, route := range s.routes.Routes() {
+		if route.Feature == "" || features[route.Feature] {
+			routes = append(routes, route.Method+" "+route.Path)
+		}
+	}
+
+	limiter := s.Config().GetLimiter()
+	return Capabilities{
+		Version:   CapabilitiesVersion,
+		NfVersion: nfVersion(),
+		Features:  features,
+		Limits: CapabilityLimits{
+			MaxRequestTimeout: s.maxRequestTimeout().Milliseconds(),
+			MaxInflight:       limiter.MaxInflight,
+			MaxQueue:          limiter.MaxQueue,
+		},
+		StrictDecoding: s.strictDecoding(),
+		Languages:      processor.Locales(),
+		Routes:         routes,
+	}
+}
+
+// HTTPGetCapabilities is read on every request, so feature toggles and limits show up immediately.
+func (s *Server) HTTPGetCapabilities(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetCapabilities")
+
+	c.JSON(http.StatusOK, s.capabilities())
+}
@@ -0,0 +1,80 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+func getCapabilities(t *testing.T, server *testutil.TestServer) sbi.Capabilities {
+	t.Helper()
+
+	status, body := server.Do(t, http.MethodGet, "/capabilities", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	var capabilities sbi.Capabilities
+	if err := json.Unmarshal(body, &capabilities); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	return capabilities
+}
+
+func Test_Capabilities(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		server := testutil.NewTestServer(t, testutil.Options{})
+		capabilities := getCapabilities(t, server)
+
+		if capabilities.Version != sbi.CapabilitiesVersion {
+			t.Errorf("Expected version %d, got %d", sbi.CapabilitiesVersion, capabilities.Version)
+		}
+		if capabilities.Limits.MaxRequestTimeout != factory.NfDefaultMaxRequestTimeout {
+			t.Errorf("Expected maxRequestTimeout %d, got %d", factory.NfDefaultMaxRequestTimeout, capabilities.Limits.MaxRequestTimeout)
+		}
+		if !capabilities.StrictDecoding {
+			t.Errorf("Expected strict decoding by default")
+		}
+		if !slices.Contains(capabilities.Languages, "zh-TW") {
+			t.Errorf("Expected zh-TW in languages, got %v", capabilities.Languages)
+		}
+		for _, route := range []string{"GET /capabilities", "GET /spyfamily/character/:Name"} {
+			if !slices.Contains(capabilities.Routes, route) {
+				t.Errorf("Expected route %s, got %v", route, capabilities.Routes)
+			}
+		}
+	})
+
+	t.Run("Reflects Configuration And Toggles", func(t *testing.T) {
+		const MAX_INFLIGHT = 4
+		const MAX_REQUEST_TIMEOUT = 1500
+
+		cfg := testutil.DefaultConfig()
+		cfg.Configuration.Features = map[string]bool{"search": false}
+		cfg.Configuration.Limiter = &factory.Limiter{MaxInflight: MAX_INFLIGHT}
+		cfg.Configuration.Sbi.MaxRequestTimeout = MAX_REQUEST_TIMEOUT
+		server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+
+		capabilities := getCapabilities(t, server)
+		if enabled, ok := capabilities.Features["search"]; !ok || enabled {
+			t.Errorf("Expected search to be listed as disabled, got %v", capabilities.Features)
+		}
+		if capabilities.Limits.MaxInflight != MAX_INFLIGHT {
+			t.Errorf("Expected maxInflight %d, got %d", MAX_INFLIGHT, capabilities.Limits.MaxInflight)
+		}
+		if capabilities.Limits.MaxRequestTimeout != MAX_REQUEST_TIMEOUT {
+			t.Errorf("Expected maxRequestTimeout %d, got %d", MAX_REQUEST_TIMEOUT, capabilities.Limits.MaxRequestTimeout)
+		}
+
+		if status, body := setFeature(t, server, "search", `{"enabled": true}`); status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, status, body)
+		}
+		if capabilities = getCapabilities(t, server); !capabilities.Features["search"] {
+			t.Errorf("Expected search to be listed as enabled, got %v", capabilities.Features)
+		}
+	})
+}
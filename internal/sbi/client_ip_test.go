@@ -0,0 +1,82 @@
+package sbi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_RealClientIP(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		headers        http.Header
+		expectedIP     string
+	}{
+		{
+			name:       "No Trusted Proxies Ignores Headers",
+			remoteAddr: "10.0.0.1:40000",
+			headers:    http.Header{"X-Forwarded-For": {"203.0.113.7"}},
+			expectedIP: "10.0.0.1",
+		},
+		{
+			name:           "Trusted Peer Uses X-Forwarded-For",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:40000",
+			headers:        http.Header{"X-Forwarded-For": {"203.0.113.7"}},
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "Trusted Peer Uses X-Real-IP",
+			trustedProxies: []string{"10.0.0.1"},
+			remoteAddr:     "10.0.0.1:40000",
+			headers:        http.Header{"X-Real-IP": {"203.0.113.8"}},
+			expectedIP:     "203.0.113.8",
+		},
+		{
+			name:           "Untrusted Peer Spoofing Headers",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "198.51.100.2:40000",
+			headers: http.Header{
+				"X-Forwarded-For": {"203.0.113.7"},
+				"X-Real-IP":       {"203.0.113.8"},
+			},
+			expectedIP: "198.51.100.2",
+		},
+		{
+			name:           "Multi-Hop Skips Trusted Hops",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:40000",
+			headers:        http.Header{"X-Forwarded-For": {"203.0.113.7, 10.0.0.2, 10.0.0.3"}},
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "Multi-Hop Ignores Client Supplied Entries",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.1:40000",
+			headers:        http.Header{"X-Forwarded-For": {"192.0.2.99, 203.0.113.7, 10.0.0.2"}},
+			expectedIP:     "203.0.113.7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testutil.DefaultConfig()
+			cfg.Configuration.Sbi.TrustedProxies = tc.trustedProxies
+			server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+
+			ginCtx := gin.CreateTestContextOnly(httptest.NewRecorder(), server.Router())
+			ginCtx.Request = testutil.NewRequest(t, http.MethodGet, "/", nil, tc.headers)
+			ginCtx.Request.RemoteAddr = tc.remoteAddr
+
+			if ip := sbi.RealClientIP(ginCtx); ip != tc.expectedIP {
+				t.Errorf("Expected client IP %s, got %s", tc.expectedIP, ip)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package consumer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/tracing"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+// ClientFactory builds the HTTP clients used for every outbound request, so timeouts, mTLS,
+// proxy, connection pool and egress policy settings are configured in one place.
+type ClientFactory struct {
+	timeout time.Duration
+	policy  *EgressPolicy
+
+	mu                  sync.RWMutex
+	transport           http.RoundTripper
+	restrictedTransport http.RoundTripper
+}
+
+// NewClientFactory refuses a proxy together with an egress policy, which can only check the addresses
+// it dials itself. For the same reason, proxies from the environment are ignored when egress is set.
+func NewClientFactory(cfg *factory.Client) (*ClientFactory, error) {
+	if cfg.Proxy != "" && cfg.Egress != nil {
+		return nil, fmt.Errorf("client proxy cannot be combined with an egress policy")
+	}
+	policy, err := NewEgressPolicy(cfg.Egress)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(cfg, policy, false)
+	if err != nil {
+		return nil, err
+	}
+	restrictedTransport, err := newTransport(cfg, policy, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientFactory{
+		timeout:             time.Duration(cfg.Timeout) * time.Millisecond,
+		policy:              policy,
+		transport:           transport,
+		restrictedTransport: restrictedTransport,
+	}, nil
+}
+
+// Client returns a client sharing the factory's transport and connection pool.
+func (f *ClientFactory) Client() *http.Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return &http.Client{
+		Transport: tracing.Transport(f.transport),
+		Timeout:   f.timeout,
+	}
+}
+
+// UntracedClient is like Client but creates no spans. It is meant for the trace exporter, whose
+// requests would otherwise be traced and exported again.
+func (f *ClientFactory) UntracedClient() *http.Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return &http.Client{
+		Transport: f.transport,
+		Timeout:   f.timeout,
+	}
+}
+
+// RestrictedClient returns a client for URLs supplied by clients of the NF, such as webhooks. Besides the
+// egress policy it refuses loopback, link-local and unspecified addresses, and it never uses the proxy,
+// since the policy can only check addresses it dials itself.
+func (f *ClientFactory) RestrictedClient() *http.Client {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return &http.Client{
+		Transport: tracing.Transport(f.restrictedTransport),
+		Timeout:   f.timeout,
+	}
+}
+
+// CheckURL refuses a client-supplied URL the restricted client would not be allowed to call.
+// Callers accepting such URLs check them before storing them.
+func (f *ClientFactory) CheckURL(rawURL string) error {
+	return f.policy.CheckURL(rawURL)
+}
+
+// Transport returns the round tripper behind every client.
+func (f *ClientFactory) Transport() http.RoundTripper {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.transport
+}
+
+// SetTransport replaces the round tripper used by clients created afterwards, e.g. with a fake in tests.
+// Restricted clients use it too, so the egress policy no longer applies.
+func (f *ClientFactory) SetTransport(transport http.RoundTripper) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transport = transport
+	f.restrictedTransport = transport
+}
+
+func newTransport(cfg *factory.Client, policy *EgressPolicy, restricted bool) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.Proxy != "" {
+		proxyURL, parseErr := url.Parse(cfg.Proxy)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parse client proxy failed: %w", parseErr)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+	if restricted || cfg.Egress != nil {
+		proxy = nil
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   time.Duration(cfg.DialTimeout) * time.Millisecond,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:               proxy,
+		DialContext:         policy.dialContext(dialer.DialContext, restricted),
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: time.Duration(cfg.TLSHandshakeTimeout) * time.Millisecond,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        cfg.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		IdleConnTimeout:     90 * time.Second,
+	}, nil
+}
+
+func newTLSConfig(cfg *factory.Client) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.Tls != nil {
+		cert, err := tls.LoadX509KeyPair(cfg.Tls.Pem, cfg.Tls.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate failed: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+		logger.ConsumerLog.Infof("Outbound requests present client certificate %s", cfg.Tls.Pem)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(filepath.Clean(cfg.CABundle))
+		if err != nil {
+			return nil, fmt.Errorf("read client CA bundle failed: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("client CA bundle %s contains no certificate", cfg.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
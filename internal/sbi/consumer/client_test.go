@@ -0,0 +1,243 @@
+package consumer_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/consumer"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+type certificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newCertificate issues a certificate signed by parent, or a self-signed CA when parent is nil.
+func newCertificate(t *testing.T, name string, parent *certificate, usage x509.ExtKeyUsage) *certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %s", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %s", err)
+	}
+
+	return &certificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %s", name, err)
+	}
+	return path
+}
+
+func get(client *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, err
+}
+
+func Test_ClientFactoryMutualTLS(t *testing.T) {
+	ca := newCertificate(t, "nf-example CA", nil, x509.ExtKeyUsageAny)
+	serverCert := newCertificate(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	clientCert := newCertificate(t, "client", ca, x509.ExtKeyUsageClientAuth)
+
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca.cert)
+	serverKeyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load server key pair: %s", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverKeyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    caPool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caBundle := writeFile(t, "ca.pem", ca.certPEM)
+
+	t.Run("With Client Certificate", func(t *testing.T) {
+		f, err := consumer.NewClientFactory(&factory.Client{
+			Tls: &factory.Tls{
+				Pem: writeFile(t, "client.pem", clientCert.certPEM),
+				Key: writeFile(t, "client.key", clientCert.keyPEM),
+			},
+			CABundle: caBundle,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create client factory: %s", err)
+		}
+
+		status, err := get(f.Client(), server.URL)
+		if err != nil {
+			t.Fatalf("Failed to send request: %s", err)
+		}
+		if status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+	})
+
+	t.Run("Without Client Certificate", func(t *testing.T) {
+		f, err := consumer.NewClientFactory(&factory.Client{CABundle: caBundle})
+		if err != nil {
+			t.Fatalf("Failed to create client factory: %s", err)
+		}
+
+		if _, err = get(f.Client(), server.URL); err == nil {
+			t.Errorf("Expected handshake to fail without a client certificate")
+		}
+	})
+
+	t.Run("Untrusted Server", func(t *testing.T) {
+		f, err := consumer.NewClientFactory(&factory.Client{})
+		if err != nil {
+			t.Fatalf("Failed to create client factory: %s", err)
+		}
+
+		var certErr *tls.CertificateVerificationError
+		if _, err = get(f.Client(), server.URL); !errors.As(err, &certErr) {
+			t.Errorf("Expected certificate verification error, got %v", err)
+		}
+	})
+}
+
+func Test_ClientFactoryTimeouts(t *testing.T) {
+	const TIMEOUT = 50
+
+	f, err := consumer.NewClientFactory(&factory.Client{
+		Timeout:             TIMEOUT,
+		TLSHandshakeTimeout: 2 * TIMEOUT,
+		MaxIdleConnsPerHost: 4,
+		MaxConnsPerHost:     8,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client factory: %s", err)
+	}
+
+	transport, ok := f.Transport().(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", f.Transport())
+	}
+	if transport.TLSHandshakeTimeout != 2*TIMEOUT*time.Millisecond {
+		t.Errorf("Expected TLS handshake timeout %v, got %v", 2*TIMEOUT*time.Millisecond, transport.TLSHandshakeTimeout)
+	}
+	if transport.MaxIdleConnsPerHost != 4 || transport.MaxConnsPerHost != 8 {
+		t.Errorf("Expected pool limits 4/8, got %d/%d", transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost)
+	}
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	_, err = get(f.Client(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout") {
+		t.Errorf("Expected client timeout, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_ClientFactorySetTransport(t *testing.T) {
+	f, err := consumer.NewClientFactory(&factory.Client{})
+	if err != nil {
+		t.Fatalf("Failed to create client factory: %s", err)
+	}
+
+	f.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusTeapot,
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}))
+
+	status, err := get(f.Client(), "http://nrf.invalid/nnrf-disc/v1/nf-instances")
+	if err != nil {
+		t.Fatalf("Failed to send request: %s", err)
+	}
+	if status != http.StatusTeapot {
+		t.Errorf("Expected status code %d, got %d", http.StatusTeapot, status)
+	}
+}
@@ -0,0 +1,177 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+// ErrEgressBlocked is returned, wrapped, for an outbound request the egress policy does not allow.
+var ErrEgressBlocked = errors.New("blocked by egress policy")
+
+// EgressPolicy decides which hosts outbound requests may reach. Host names are matched before
+// resolution and IPs after it, at dial time, so a name re-resolving to another IP is checked again.
+//
+// A denied host name or network always blocks. When an allow list is set, a request must match one
+// of its host names or reach an IP in one of its networks. Restricted requests, made to URLs supplied
+// by clients, are also kept away from loopback, link-local and unspecified addresses unless allowed explicitly.
+type EgressPolicy struct {
+	allowHosts []string
+	allowNets  []netip.Prefix
+	denyHosts  []string
+	denyNets   []netip.Prefix
+	lookup     func(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+func NewEgressPolicy(cfg *factory.Egress) (*EgressPolicy, error) {
+	p := &EgressPolicy{lookup: net.DefaultResolver.LookupNetIP}
+	if cfg == nil {
+		return p, nil
+	}
+
+	var err error
+	if p.allowHosts, p.allowNets, err = parseEgressEntries(cfg.Allow); err != nil {
+		return nil, fmt.Errorf("parse egress allow list failed: %w", err)
+	}
+	if p.denyHosts, p.denyNets, err = parseEgressEntries(cfg.Deny); err != nil {
+		return nil, fmt.Errorf("parse egress deny list failed: %w", err)
+	}
+	return p, nil
+}
+
+func parseEgressEntries(entries []string) ([]string, []netip.Prefix, error) {
+	var hosts []string
+	var nets []netip.Prefix
+	for _, entry := range entries {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			nets = append(nets, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			nets = append(nets, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		if entry == "" || strings.ContainsAny(entry, "/:") {
+			return nil, nil, fmt.Errorf("%q is not a host name, IP or CIDR", entry)
+		}
+		hosts = append(hosts, strings.ToLower(entry))
+	}
+	return hosts, nets, nil
+}
+
+// CheckURL tells whether a URL supplied by a client may be stored and called later, so it can be
+// refused up front. The IPs a host name resolves to are only known, and checked, when it is dialed.
+func (p *EgressPolicy) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEgressBlocked, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q is not http or https", ErrEgressBlocked, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: URL has no host", ErrEgressBlocked)
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.checkAddr(host, addr, false, true)
+	}
+	if matchHost(p.denyHosts, host) {
+		return fmt.Errorf("%w: host [%s] is denied", ErrEgressBlocked, host)
+	}
+	if len(p.allowHosts) > 0 && len(p.allowNets) == 0 && !matchHost(p.allowHosts, host) {
+		return fmt.Errorf("%w: host [%s] is not on the allow list", ErrEgressBlocked, host)
+	}
+	return nil
+}
+
+// checkAddr checks one IP a host resolved to. hostAllowed tells whether the host name is on the allow list.
+func (p *EgressPolicy) checkAddr(host string, addr netip.Addr, hostAllowed, restricted bool) error {
+	addr = addr.Unmap()
+	if matchNet(p.denyNets, addr) {
+		return fmt.Errorf("%w: [%s] resolves to denied address %s", ErrEgressBlocked, host, addr)
+	}
+
+	allowed := hostAllowed || matchNet(p.allowNets, addr)
+	if !allowed && (len(p.allowHosts) > 0 || len(p.allowNets) > 0) {
+		return fmt.Errorf("%w: [%s] (%s) is not on the allow list", ErrEgressBlocked, host, addr)
+	}
+	if !allowed && restricted && (addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsUnspecified()) {
+		return fmt.Errorf("%w: [%s] resolves to internal address %s", ErrEgressBlocked, host, addr)
+	}
+	return nil
+}
+
+// dialContext wraps dial so every connection goes to an IP the policy allows. The host is resolved here
+// and the checked IP is dialed directly, leaving no second lookup an attacker's DNS could answer differently.
+func (p *EgressPolicy) dialContext(dial func(ctx context.Context, network, address string) (net.Conn, error),
+	restricted bool,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if matchHost(p.denyHosts, host) {
+			return nil, fmt.Errorf("%w: host [%s] is denied", ErrEgressBlocked, host)
+		}
+		hostAllowed := matchHost(p.allowHosts, host)
+
+		var addrs []netip.Addr
+		if addr, parseErr := netip.ParseAddr(host); parseErr == nil {
+			addrs = []netip.Addr{addr}
+		} else if addrs, err = p.lookup(ctx, "ip", host); err != nil {
+			return nil, err
+		}
+
+		var errs []error
+		for _, addr := range addrs {
+			if err = p.checkAddr(host, addr, hostAllowed, restricted); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			conn, dialErr := dial(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+			if dialErr == nil {
+				return conn, nil
+			}
+			errs = append(errs, dialErr)
+		}
+		if len(errs) == 0 {
+			return nil, fmt.Errorf("no address found for [%s]", host)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// matchHost matches a host name against names and "*.domain" wildcards, which match any subdomain.
+func matchHost(hosts []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range hosts {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNet(nets []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range nets {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
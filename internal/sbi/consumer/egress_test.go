@@ -0,0 +1,154 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/consumer"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+func newEgressFactory(t *testing.T, egress *factory.Egress) *consumer.ClientFactory {
+	t.Helper()
+
+	f, err := consumer.NewClientFactory(&factory.Client{Egress: egress})
+	if err != nil {
+		t.Fatalf("Failed to create client factory: %s", err)
+	}
+	return f
+}
+
+// resolveTo makes every host name resolve to addr.
+func resolveTo(addr string) func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	return func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr(addr)}, nil
+	}
+}
+
+func Test_EgressCheckURL(t *testing.T) {
+	f := newEgressFactory(t, &factory.Egress{Deny: []string{"metadata.internal", "*.corp.example"}})
+
+	testCases := []struct {
+		name    string
+		url     string
+		blocked bool
+	}{
+		{"Cloud Metadata", "http://169.254.169.254/latest/meta-data/", true},
+		{"Loopback", "http://127.0.0.1:8080/hook", true},
+		{"IPv6 Loopback", "http://[::1]/hook", true},
+		{"IPv4-Mapped Loopback", "http://[::ffff:127.0.0.1]/hook", true},
+		{"Unspecified", "http://0.0.0.0/hook", true},
+		{"Denied Host", "https://metadata.internal/hook", true},
+		{"Denied Wildcard", "https://git.corp.example/hook", true},
+		{"Not A Web URL", "file:///etc/passwd", true},
+		{"Public Host", "https://hooks.example.com/hook", false},
+		{"Public IP", "http://203.0.113.7/hook", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := f.CheckURL(tc.url)
+			if blocked := errors.Is(err, consumer.ErrEgressBlocked); blocked != tc.blocked {
+				t.Errorf("Expected %s blocked to be %v, got %v", tc.url, tc.blocked, err)
+			}
+		})
+	}
+}
+
+func Test_EgressDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %s", err)
+	}
+	// hookURL names the test server by a host name, resolved by the fake lookup.
+	hookURL := "http://hooks.example:" + serverURL.Port() + "/hook"
+
+	t.Run("Trusted Client Reaches Loopback", func(t *testing.T) {
+		f := newEgressFactory(t, nil)
+		if status, err := get(f.Client(), server.URL); err != nil || status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d (%v)", http.StatusOK, status, err)
+		}
+	})
+
+	t.Run("Restricted Client Refuses Loopback", func(t *testing.T) {
+		f := newEgressFactory(t, nil)
+		if _, err := get(f.RestrictedClient(), server.URL); !errors.Is(err, consumer.ErrEgressBlocked) {
+			t.Errorf("Expected request to be blocked, got %v", err)
+		}
+	})
+
+	t.Run("Rebinding To Loopback", func(t *testing.T) {
+		f := newEgressFactory(t, nil)
+		if err := f.CheckURL(hookURL); err != nil {
+			t.Fatalf("Expected %s to pass the URL check, got %s", hookURL, err)
+		}
+
+		// The name passed the check above, then resolves to loopback when it is called.
+		f.SetLookup(resolveTo("127.0.0.1"))
+		if _, err := get(f.RestrictedClient(), hookURL); !errors.Is(err, consumer.ErrEgressBlocked) {
+			t.Errorf("Expected request to be blocked, got %v", err)
+		}
+	})
+
+	t.Run("Denied Host", func(t *testing.T) {
+		f := newEgressFactory(t, &factory.Egress{Deny: []string{"hooks.example"}})
+		f.SetLookup(resolveTo("127.0.0.1"))
+		if _, err := get(f.Client(), hookURL); !errors.Is(err, consumer.ErrEgressBlocked) {
+			t.Errorf("Expected request to be blocked, got %v", err)
+		}
+	})
+
+	t.Run("Denied Network", func(t *testing.T) {
+		f := newEgressFactory(t, &factory.Egress{Deny: []string{"127.0.0.0/8"}})
+		if _, err := get(f.Client(), server.URL); !errors.Is(err, consumer.ErrEgressBlocked) {
+			t.Errorf("Expected request to be blocked, got %v", err)
+		}
+	})
+
+	t.Run("Allowed Host", func(t *testing.T) {
+		f := newEgressFactory(t, &factory.Egress{Allow: []string{"hooks.example"}})
+		f.SetLookup(resolveTo("127.0.0.1"))
+		if status, err := get(f.RestrictedClient(), hookURL); err != nil || status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d (%v)", http.StatusOK, status, err)
+		}
+		if _, err := get(f.Client(), "http://other.example:"+serverURL.Port()); !errors.Is(err, consumer.ErrEgressBlocked) {
+			t.Errorf("Expected host off the allow list to be blocked, got %v", err)
+		}
+	})
+
+	t.Run("Allowed Network", func(t *testing.T) {
+		f := newEgressFactory(t, &factory.Egress{Allow: []string{"127.0.0.1/32"}})
+		if status, err := get(f.RestrictedClient(), server.URL); err != nil || status != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d (%v)", http.StatusOK, status, err)
+		}
+	})
+}
+
+func Test_EgressProxy(t *testing.T) {
+	t.Run("Configured Proxy Is Refused", func(t *testing.T) {
+		_, err := consumer.NewClientFactory(&factory.Client{
+			Proxy:  "http://proxy.example:3128",
+			Egress: &factory.Egress{Deny: []string{"metadata.internal"}},
+		})
+		if err == nil {
+			t.Errorf("Expected a proxy combined with egress to be refused")
+		}
+	})
+
+	t.Run("Environment Proxy Is Ignored", func(t *testing.T) {
+		f := newEgressFactory(t, &factory.Egress{Deny: []string{"metadata.internal"}})
+		transport, ok := f.Transport().(*http.Transport)
+		if !ok {
+			t.Fatalf("Expected an *http.Transport, got %T", f.Transport())
+		}
+		if transport.Proxy != nil {
+			t.Errorf("Expected no proxy when egress is set")
+		}
+	})
+}
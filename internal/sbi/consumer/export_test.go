@@ -0,0 +1,11 @@
+package consumer
+
+import (
+	"context"
+	"net/netip"
+)
+
+// SetLookup replaces the DNS lookup of the egress policy, so tests can resolve names to any IP.
+func (f *ClientFactory) SetLookup(lookup func(ctx context.Context, network, host string) ([]netip.Addr, error)) {
+	f.policy.lookup = lookup
+}
@@ -0,0 +1,115 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_RequestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const MAX_TIMEOUT = 100 * time.Millisecond
+
+	router := gin.New()
+	router.Use(sbi.RequestDeadline(MAX_TIMEOUT))
+	router.GET("/slow", func(c *gin.Context) {
+		// artificial delay which gives up once the request deadline passes
+		select {
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		case <-c.Request.Context().Done():
+		}
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); !ok {
+			c.String(http.StatusOK, "no deadline")
+			return
+		}
+		c.String(http.StatusOK, "deadline")
+	})
+
+	testCases := []struct {
+		name           string
+		path           string
+		timeout        string
+		expectedStatus int
+		expectedCode   processor.ErrorCode
+		expectedBody   string
+	}{
+		{
+			name:           "Deadline Fires Mid-Processing",
+			path:           "/slow",
+			timeout:        "20",
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   processor.ErrRequestTimeout,
+		},
+		{
+			name:           "Timeout Is Capped",
+			path:           "/slow",
+			timeout:        "60000",
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   processor.ErrRequestTimeout,
+		},
+		{
+			name:           "Invalid Header",
+			path:           "/fast",
+			timeout:        "soon",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   processor.ErrInvalidHeader,
+		},
+		{
+			name:           "Fast Request Unaffected",
+			path:           "/fast",
+			timeout:        "1000",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "deadline",
+		},
+		{
+			name:           "No Header Unaffected",
+			path:           "/fast",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "no deadline",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRecorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.timeout != "" {
+				req.Header.Set("X-Request-Timeout", tc.timeout)
+			}
+
+			start := time.Now()
+			router.ServeHTTP(httpRecorder, req)
+
+			if httpRecorder.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, httpRecorder.Code)
+			}
+			if elapsed := time.Since(start); elapsed > 5*MAX_TIMEOUT {
+				t.Errorf("Expected the deadline to stop the request, took %s", elapsed)
+			}
+
+			if tc.expectedCode == "" {
+				if httpRecorder.Body.String() != tc.expectedBody {
+					t.Errorf("Expected body %s, got %s", tc.expectedBody, httpRecorder.Body.String())
+				}
+				return
+			}
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Code != tc.expectedCode {
+				t.Errorf("Expected code %s, got %s", tc.expectedCode, problem.Code)
+			}
+		})
+	}
+}
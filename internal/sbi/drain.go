@@ -0,0 +1,124 @@
+package sbi
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/perf"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+)
+
+// Drainer takes the NF out of service before a blue/green switch. Once draining, /ready reports 503
+// so the load balancer stops sending traffic, and after the grace period new requests are rejected
+// with 503. Requests already being processed always complete.
+type Drainer struct {
+	gracePeriod time.Duration
+	retryAfter  int
+
+	mu    sync.RWMutex
+	since time.Time
+
+	connections atomic.Int64
+}
+
+type DrainStatus struct {
+	Draining      bool       `json:"draining"`
+	Since         *time.Time `json:"since,omitempty"`
+	GracePeriodMs float64    `json:"gracePeriodMs"`
+	Inflight      int64      `json:"inflight"`
+	Connections   int64      `json:"connections"`
+}
+
+func NewDrainer(cfg *factory.Drain) *Drainer {
+	d := &Drainer{
+		gracePeriod: time.Duration(cfg.GracePeriod) * time.Millisecond,
+		retryAfter:  cfg.RetryAfter,
+	}
+	if cfg.GracePeriod == 0 {
+		d.gracePeriod = factory.NfDefaultDrainGracePeriod * time.Millisecond
+	}
+	if d.retryAfter == 0 {
+		d.retryAfter = factory.NfDefaultDrainRetryAfter
+	}
+	return d
+}
+
+// Drain starts draining. Draining again keeps the original start time.
+func (d *Drainer) Drain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.since.IsZero() {
+		d.since = time.Now()
+	}
+}
+
+func (d *Drainer) Undrain() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.since = time.Time{}
+}
+
+func (d *Drainer) Draining() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return !d.since.IsZero()
+}
+
+// rejecting reports whether the grace period of a drain has passed.
+func (d *Drainer) rejecting() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return !d.since.IsZero() && time.Since(d.since) >= d.gracePeriod
+}
+
+// Connections returns the number of open client connections, when tracked by TrackConnections.
+func (d *Drainer) Connections() int64 {
+	return d.connections.Load()
+}
+
+// TrackConnections counts the open connections of an HTTP server.
+func (d *Drainer) TrackConnections(server *http.Server) {
+	server.ConnState = func(_ net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateNew:
+			d.connections.Add(1)
+		case http.StateHijacked, http.StateClosed:
+			d.connections.Add(-1)
+		}
+	}
+}
+
+func (d *Drainer) Status(inflight int64) DrainStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status := DrainStatus{
+		Draining:      !d.since.IsZero(),
+		GracePeriodMs: perf.Milliseconds(d.gracePeriod),
+		Inflight:      inflight,
+		Connections:   d.connections.Load(),
+	}
+	if status.Draining {
+		since := d.since
+		status.Since = &since
+	}
+	return status
+}
+
+func (d *Drainer) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if controlPath(c.Request.URL.Path) || !d.rejecting() {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", strconv.Itoa(d.retryAfter))
+		processor.RespondError(c, processor.ErrDraining, "The NF is draining and accepts no new requests")
+	}
+}
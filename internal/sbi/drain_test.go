@@ -0,0 +1,127 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+)
+
+func Test_Drainer(t *testing.T) {
+	t.Run("In-Flight Request Completes", func(t *testing.T) {
+		drainer := sbi.NewDrainer(&factory.Drain{GracePeriod: 1, RetryAfter: 9})
+
+		entered := make(chan struct{}, 1)
+		release := make(chan struct{})
+		router := gin.New()
+		router.Use(drainer.Middleware())
+		router.GET("/block", func(c *gin.Context) {
+			entered <- struct{}{}
+			<-release
+			c.Status(http.StatusOK)
+		})
+		router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+		// Cleanups run last-in first-out, so a failing test releases the handler before closing the server.
+		releaseHandler := sync.OnceFunc(func() { close(release) })
+		t.Cleanup(releaseHandler)
+
+		statuses := make(chan int, 1)
+		go func() {
+			if resp := sendRequest(t, server.URL+"/block"); resp != nil {
+				statuses <- resp.StatusCode
+			}
+			close(statuses)
+		}()
+		<-entered
+
+		drainer.Drain()
+		var resp *http.Response
+		waitFor(t, "new requests to be rejected", func() bool {
+			resp = sendRequest(t, server.URL+"/ok")
+			return resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+		})
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "9" {
+			t.Errorf("Expected Retry-After 9, got %s", retryAfter)
+		}
+
+		releaseHandler()
+		if status := <-statuses; status != http.StatusOK {
+			t.Errorf("Expected in-flight request to complete with %d, got %d", http.StatusOK, status)
+		}
+	})
+
+	t.Run("Accept During Grace Period", func(t *testing.T) {
+		drainer := sbi.NewDrainer(&factory.Drain{GracePeriod: 60000})
+		router := gin.New()
+		router.Use(drainer.Middleware())
+		router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+		server := httptest.NewServer(router)
+		t.Cleanup(server.Close)
+
+		drainer.Drain()
+		if resp := sendRequest(t, server.URL+"/ok"); resp != nil && resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+	})
+}
+
+func Test_DrainEndpoints(t *testing.T) {
+	cfg := testutil.DefaultConfig()
+	cfg.Configuration.Drain = &factory.Drain{GracePeriod: 1}
+	server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+
+	status, body := server.Do(t, http.MethodPost, "/admin/drain", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	var drain sbi.DrainStatus
+	if err := json.Unmarshal(body, &drain); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if !drain.Draining || drain.Since == nil || drain.Inflight != 0 || drain.GracePeriodMs != 1 {
+		t.Errorf("Expected draining with nothing in flight, got %+v", drain)
+	}
+
+	status, body = server.Do(t, http.MethodGet, "/ready", nil)
+	var ready sbi.ReadyResponse
+	if err := json.Unmarshal(body, &ready); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if status != http.StatusServiceUnavailable || ready.Ready || !ready.Draining {
+		t.Errorf("Expected not ready while draining, got %d %+v", status, ready)
+	}
+
+	waitFor(t, "new requests to be rejected", func() bool {
+		status, body = server.Do(t, http.MethodGet, "/spyfamily/character/Anya", nil)
+		return status == http.StatusServiceUnavailable
+	})
+	var problem processor.ProblemDetails
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if problem.Code != processor.ErrDraining {
+		t.Errorf("Expected code %s, got %s", processor.ErrDraining, problem.Code)
+	}
+	if status, _ = server.Do(t, http.MethodGet, "/info", nil); status != http.StatusOK {
+		t.Errorf("Expected /info to stay reachable, got %d", status)
+	}
+
+	if status, _ = server.Do(t, http.MethodPost, "/admin/undrain", nil); status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	if status, _ = server.Do(t, http.MethodGet, "/ready", nil); status != http.StatusOK {
+		t.Errorf("Expected ready after undrain, got %d", status)
+	}
+	if status, _ = server.Do(t, http.MethodGet, "/spyfamily/character/Anya", nil); status != http.StatusOK {
+		t.Errorf("Expected service restored after undrain, got %d", status)
+	}
+}
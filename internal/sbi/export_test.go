@@ -0,0 +1,16 @@
+package sbi
+
+import "github.com/gin-gonic/gin"
+
+var (
+	RequestDeadline = requestDeadline
+	RecoverPanic    = recoverPanic
+)
+
+func (s *Server) FeatureGuard(feature string) gin.HandlerFunc {
+	return s.featureGuard(feature)
+}
+
+func (s *Server) Router() *gin.Engine {
+	return s.router
+}
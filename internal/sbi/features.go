@@ -0,0 +1,90 @@
+package sbi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/gin-gonic/gin"
+)
+
+type FeatureInfo struct {
+	Name    string   `json:"name"`
+	Enabled bool     `json:"enabled"`
+	Routes  []string `json:"routes,omitempty"`
+}
+
+type FeatureRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// featureGuard rejects requests to a route while its feature is disabled, as if the route did not
+// exist or, with featureDisabledStatus 501, naming the feature. Flags are read on every request,
+// so toggling one takes effect immediately.
+func (s *Server) featureGuard(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.Context().FeatureEnabled(feature) {
+			return
+		}
+		if s.Config().Configuration.FeatureDisabledStatus == http.StatusNotImplemented {
+			processor.RespondError(c, processor.ErrFeatureDisabled, fmt.Sprintf("Feature [%s] is disabled", feature))
+			return
+		}
+		processor.RespondError(c, processor.ErrRouteNotFound,
+			fmt.Sprintf("%s %s is not served", c.Request.Method, c.Request.URL.Path))
+	}
+}
+
+// features lists every flag that is configured or guards a route, sorted by name.
+func (s *Server) features() []FeatureInfo {
+	guarded := s.routes.Features()
+	flags := s.Context().GetFeatures()
+	for name := range guarded {
+		if _, ok := flags[name]; !ok {
+			flags[name] = false
+		}
+	}
+
+	features := make([]FeatureInfo, 0, len(flags))
+	for name, enabled := range flags {
+		info := FeatureInfo{Name: name, Enabled: enabled}
+		for _, route := range guarded[name] {
+			info.Routes = append(info.Routes, route.Method+" "+route.Path)
+		}
+		features = append(features, info)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return features
+}
+
+func (s *Server) HTTPGetFeatures(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPGetFeatures")
+
+	c.JSON(http.StatusOK, s.features())
+}
+
+// HTTPSetFeature turns a known feature flag on or off at runtime.
+func (s *Server) HTTPSetFeature(c *gin.Context) {
+	logger.SBILog.Infof("In HTTPSetFeature")
+
+	name := c.Param("Name")
+	var req FeatureRequest
+	if !s.bindStrict(c, &req) {
+		return
+	}
+
+	for _, info := range s.features() {
+		if info.Name != name {
+			continue
+		}
+		s.Context().SetFeature(name, *req.Enabled)
+		logger.SBILog.Warnf("Feature [%s] is set to [%v]", name, *req.Enabled)
+
+		info.Enabled = *req.Enabled
+		c.JSON(http.StatusOK, info)
+		return
+	}
+	processor.RespondError(c, processor.ErrFeatureNotFound, fmt.Sprintf("Feature [%s] is not configured", name))
+}
@@ -0,0 +1,110 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+// featureRouter serves GET /lab/search, flagged with the search feature, guarded by server.
+func featureRouter(t *testing.T, server *testutil.TestServer) *httptest.Server {
+	t.Helper()
+
+	registry := sbi.NewRouteRegistry()
+	err := registry.Register(sbi.NewModule("lab", "/lab", []sbi.Route{{
+		Name:    "Search",
+		Method:  http.MethodGet,
+		Pattern: "/search",
+		APIFunc: func(c *gin.Context) { c.Status(http.StatusOK) },
+		Feature: "search",
+	}}))
+	if err != nil {
+		t.Fatalf("Failed to register module: %s", err)
+	}
+	router := gin.New()
+	registry.Apply(router, server.Server.FeatureGuard)
+
+	lab := httptest.NewServer(router)
+	t.Cleanup(lab.Close)
+	return lab
+}
+
+func setFeature(t *testing.T, server *testutil.TestServer, name, body string) (int, []byte) {
+	t.Helper()
+	return server.Do(t, http.MethodPut, "/admin/features/"+name, strings.NewReader(body))
+}
+
+func Test_Features(t *testing.T) {
+	t.Run("Toggle At Runtime", func(t *testing.T) {
+		cfg := testutil.DefaultConfig()
+		cfg.Configuration.Features = map[string]bool{"search": false, "export": true}
+		server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+		lab := featureRouter(t, server)
+
+		if resp := sendRequest(t, lab.URL+"/lab/search"); resp != nil && resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected disabled route to answer %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+
+		status, body := setFeature(t, server, "search", `{"enabled": true}`)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, status, body)
+		}
+		if resp := sendRequest(t, lab.URL+"/lab/search"); resp != nil && resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected enabled route to answer %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		if status, _ = setFeature(t, server, "search", `{"enabled": false}`); status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+		if resp := sendRequest(t, lab.URL+"/lab/search"); resp != nil && resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected disabled route to answer %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+
+		status, body = server.Do(t, http.MethodGet, "/admin/features", nil)
+		if status != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+		}
+		var features []sbi.FeatureInfo
+		if err := json.Unmarshal(body, &features); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if len(features) != 2 || features[0].Name != "export" || !features[0].Enabled ||
+			features[1].Name != "search" || features[1].Enabled {
+			t.Errorf("Expected export enabled and search disabled, got %+v", features)
+		}
+	})
+
+	t.Run("Not Implemented Status", func(t *testing.T) {
+		cfg := testutil.DefaultConfig()
+		cfg.Configuration.FeatureDisabledStatus = http.StatusNotImplemented
+		server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+		lab := featureRouter(t, server)
+
+		if resp := sendRequest(t, lab.URL+"/lab/search"); resp != nil && resp.StatusCode != http.StatusNotImplemented {
+			t.Errorf("Expected disabled route to answer %d, got %d", http.StatusNotImplemented, resp.StatusCode)
+		}
+	})
+
+	t.Run("Unknown Feature", func(t *testing.T) {
+		server := testutil.NewTestServer(t, testutil.Options{})
+
+		status, body := setFeature(t, server, "websocket", `{"enabled": true}`)
+		if status != http.StatusNotFound {
+			t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, status)
+		}
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Code != processor.ErrFeatureNotFound {
+			t.Errorf("Expected code %s, got %s", processor.ErrFeatureNotFound, problem.Code)
+		}
+	})
+}
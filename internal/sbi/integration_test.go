@@ -0,0 +1,103 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+)
+
+func Test_Integration(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedCode   processor.ErrorCode
+		goldenFile     string
+	}{
+		{
+			name:           "Default Hello",
+			method:         http.MethodGet,
+			path:           "/default/",
+			expectedStatus: http.StatusOK,
+			goldenFile:     "testdata/default_hello.golden.json",
+		},
+		{
+			name:           "SPYxFAMILY Hello",
+			method:         http.MethodGet,
+			path:           "/spyfamily/",
+			expectedStatus: http.StatusOK,
+			goldenFile:     "testdata/spyfamily_hello.golden.json",
+		},
+		{
+			name:           "Find Character That Exists",
+			method:         http.MethodGet,
+			path:           "/spyfamily/character/Anya",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Character: Anya Forger",
+		},
+		{
+			name:           "Find Character That Does Not Exist",
+			method:         http.MethodGet,
+			path:           "/spyfamily/character/Andy",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   processor.ErrCharacterNotFound,
+		},
+		{
+			name:           "Character Without Name",
+			method:         http.MethodGet,
+			path:           "/spyfamily/character/",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   processor.ErrRouteNotFound,
+		},
+		{
+			name:           "Unsupported Method",
+			method:         http.MethodPost,
+			path:           "/spyfamily/character/Anya",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   processor.ErrRouteNotFound,
+		},
+		{
+			name:           "Unknown Route",
+			method:         http.MethodGet,
+			path:           "/unknown/",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   processor.ErrRouteNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := server.Do(t, tc.method, tc.path, nil)
+
+			if status != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, status)
+			}
+
+			if tc.expectedBody != "" && strings.TrimSpace(string(body)) != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+
+			if tc.expectedCode != "" {
+				var problem processor.ProblemDetails
+				if err := json.Unmarshal(body, &problem); err != nil {
+					t.Fatalf("Failed to unmarshal body: %s", err)
+				}
+				if problem.Code != tc.expectedCode {
+					t.Errorf("Expected code %s, got %s", tc.expectedCode, problem.Code)
+				}
+			}
+
+			if tc.goldenFile != "" {
+				testutil.AssertJSONGolden(t, body, tc.goldenFile)
+			}
+		})
+	}
+}
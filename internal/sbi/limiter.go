@@ -0,0 +1,125 @@
+package sbi
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultLimiterRetryAfter = 1
+
+// Limiter bounds the number of requests processed at the same time. Requests beyond the bound
+// wait in a queue of limited length for up to the queue timeout, and are rejected with 503 otherwise.
+// A limiter without a maximum only counts requests.
+type Limiter struct {
+	slots        chan struct{}
+	maxQueue     int64
+	queueTimeout time.Duration
+	retryAfter   int
+
+	inflight atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Int64
+}
+
+func NewLimiter(cfg *factory.Limiter) *Limiter {
+	l := &Limiter{
+		maxQueue:     int64(cfg.MaxQueue),
+		queueTimeout: time.Duration(cfg.QueueTimeout) * time.Millisecond,
+		retryAfter:   cfg.RetryAfter,
+	}
+	if cfg.MaxInflight > 0 {
+		l.slots = make(chan struct{}, cfg.MaxInflight)
+	}
+	if l.retryAfter == 0 {
+		l.retryAfter = defaultLimiterRetryAfter
+	}
+	return l
+}
+
+// Inflight returns the number of requests being processed.
+func (l *Limiter) Inflight() int64 {
+	return l.inflight.Load()
+}
+
+// Queued returns the number of requests waiting for a free slot.
+func (l *Limiter) Queued() int64 {
+	return l.queued.Load()
+}
+
+// Rejected returns the number of requests answered with 503 since the limiter was created.
+func (l *Limiter) Rejected() int64 {
+	return l.rejected.Load()
+}
+
+// controlPath reports the admin and observability endpoints, which stay reachable while the NF
+// is saturated or draining.
+func controlPath(path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	return strings.HasPrefix(path, adminPathPrefix) || path == metricsPath || path == infoPath ||
+		path == readyPath || path == healthPath
+}
+
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if controlPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		if !l.acquire(c) {
+			l.rejected.Add(1)
+			c.Header("Retry-After", strconv.Itoa(l.retryAfter))
+			processor.RespondError(c, processor.ErrRateLimited, "Too many concurrent requests")
+			return
+		}
+		defer l.release()
+
+		c.Next()
+	}
+}
+
+func (l *Limiter) acquire(c *gin.Context) bool {
+	if l.slots == nil {
+		l.inflight.Add(1)
+		return true
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inflight.Add(1)
+		return true
+	default:
+	}
+
+	if l.queued.Add(1) > l.maxQueue {
+		l.queued.Add(-1)
+		return false
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inflight.Add(1)
+		return true
+	case <-timer.C:
+		return false
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+func (l *Limiter) release() {
+	l.inflight.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
@@ -0,0 +1,158 @@
+package sbi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+)
+
+// blockingServer serves a handler that holds every request until release is closed.
+func blockingServer(t *testing.T, limiter *sbi.Limiter) (*httptest.Server, <-chan struct{}, chan struct{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	entered := make(chan struct{}, 16)
+	release := make(chan struct{})
+	router := gin.New()
+	router.Use(limiter.Middleware())
+	router.GET("/block", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, entered, release
+}
+
+func sendRequest(t *testing.T, url string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Errorf("Failed to create request: %s", err)
+		return nil
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("Failed to send request: %s", err)
+		return nil
+	}
+	if err = resp.Body.Close(); err != nil {
+		t.Errorf("Failed to close response body: %s", err)
+	}
+	return resp
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func Test_Limiter(t *testing.T) {
+	const MAX_INFLIGHT = 2
+
+	t.Run("Queue Then Reject", func(t *testing.T) {
+		limiter := sbi.NewLimiter(&factory.Limiter{
+			MaxInflight:  MAX_INFLIGHT,
+			MaxQueue:     1,
+			QueueTimeout: 5000,
+			RetryAfter:   7,
+		})
+		server, entered, release := blockingServer(t, limiter)
+
+		var wg sync.WaitGroup
+		statuses := make(chan int, MAX_INFLIGHT+1)
+		for i := 0; i < MAX_INFLIGHT+1; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := sendRequest(t, server.URL+"/block"); resp != nil {
+					statuses <- resp.StatusCode
+				}
+			}()
+		}
+
+		for i := 0; i < MAX_INFLIGHT; i++ {
+			<-entered
+		}
+		waitFor(t, "a queued request", func() bool { return limiter.Queued() == 1 })
+		if inflight := limiter.Inflight(); inflight != MAX_INFLIGHT {
+			t.Errorf("Expected %d in-flight requests, got %d", MAX_INFLIGHT, inflight)
+		}
+
+		resp := sendRequest(t, server.URL+"/block")
+		if resp == nil {
+			t.FailNow()
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+		}
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "7" {
+			t.Errorf("Expected Retry-After 7, got %s", retryAfter)
+		}
+
+		close(release)
+		wg.Wait()
+		close(statuses)
+		for status := range statuses {
+			if status != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+			}
+		}
+
+		if limiter.Inflight() != 0 || limiter.Queued() != 0 {
+			t.Errorf("Expected counters to return to zero, got inflight %d queued %d",
+				limiter.Inflight(), limiter.Queued())
+		}
+		if rejected := limiter.Rejected(); rejected != 1 {
+			t.Errorf("Expected 1 rejected request, got %d", rejected)
+		}
+	})
+
+	t.Run("Queue Timeout", func(t *testing.T) {
+		limiter := sbi.NewLimiter(&factory.Limiter{
+			MaxInflight:  MAX_INFLIGHT,
+			MaxQueue:     1,
+			QueueTimeout: 50,
+		})
+		server, entered, release := blockingServer(t, limiter)
+
+		var wg sync.WaitGroup
+		for i := 0; i < MAX_INFLIGHT; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sendRequest(t, server.URL+"/block")
+			}()
+			<-entered
+		}
+
+		resp := sendRequest(t, server.URL+"/block")
+		if resp != nil && resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected status code %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+		}
+
+		close(release)
+		wg.Wait()
+		if limiter.Inflight() != 0 || limiter.Queued() != 0 {
+			t.Errorf("Expected counters to return to zero, got inflight %d queued %d",
+				limiter.Inflight(), limiter.Queued())
+		}
+	})
+}
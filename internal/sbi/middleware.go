@@ -0,0 +1,201 @@
+package sbi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/timing"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const adminPathPrefix = "/admin/"
+
+// isMutatingMethod reports whether the HTTP method may change server state.
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// maintenanceGuard rejects mutating requests with 503 while maintenance mode is on.
+// Reads and admin routes keep working so operators can inspect and turn the mode off.
+func (s *Server) maintenanceGuard() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) || strings.HasPrefix(c.Request.URL.Path, adminPathPrefix) {
+			c.Next()
+			return
+		}
+
+		maintenance := s.Context().GetMaintenance()
+		if !maintenance.Enabled {
+			c.Next()
+			return
+		}
+
+		detail := maintenance.Message
+		if detail == "" {
+			detail = "The NF is under maintenance"
+		}
+		c.Header("Retry-After", strconv.Itoa(maintenance.RetryAfter))
+		processor.RespondError(c, processor.ErrMaintenance, detail)
+	}
+}
+
+// countInflight counts the requests being served so shutdown can report them. Unlike the limiter
+// it runs for every path.
+func (s *Server) countInflight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.inflight.Add(1)
+		defer s.inflight.Add(-1)
+		c.Next()
+	}
+}
+
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// requestDeadline bounds the request context by the X-Request-Timeout header, in milliseconds,
+// capped by maxTimeout. Handlers that give up once the deadline passes without writing a response
+// are answered with 504.
+func requestDeadline(maxTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(requestTimeoutHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		millis, err := strconv.Atoi(header)
+		if err != nil || millis <= 0 {
+			processor.RespondError(c, processor.ErrInvalidHeader,
+				fmt.Sprintf("%s [%s] is not a positive number of milliseconds", requestTimeoutHeader, header))
+			return
+		}
+		timeout := min(time.Duration(millis)*time.Millisecond, maxTimeout)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			processor.RespondError(c, processor.ErrRequestTimeout,
+				fmt.Sprintf("Request deadline of %s exceeded", timeout))
+		}
+	}
+}
+
+// observeDuration records the latency of every request, labelled by its route pattern with both
+// slash forms sharing a series. It must run after the tracing middleware so a sampled request can
+// attach its trace ID as an exemplar.
+func observeDuration(histogram *prometheus.HistogramVec) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := normalizeRoute(c.FullPath())
+		observer := histogram.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+		seconds := time.Since(start).Seconds()
+
+		spanContext := trace.SpanContextFromContext(c.Request.Context())
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && spanContext.IsSampled() {
+			exemplarObserver.ObserveWithExemplar(seconds, prometheus.Labels{"trace_id": spanContext.TraceID().String()})
+			return
+		}
+		observer.Observe(seconds)
+	}
+}
+
+func normalizeRoute(route string) string {
+	if route == "" {
+		return "unmatched"
+	}
+	if route != "/" {
+		return strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+const debugTimingHeader = "X-Debug-Timing"
+
+// serverTiming reports the phases of a request in the Server-Timing header, for every request when
+// enabled in the configuration or for requests sending X-Debug-Timing: 1.
+func serverTiming(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled && c.GetHeader(debugTimingHeader) != "1" {
+			c.Next()
+			return
+		}
+
+		ctx, recorder := timing.WithRecorder(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		writer := &timingWriter{ResponseWriter: c.Writer, recorder: recorder}
+		c.Writer = writer
+
+		c.Next()
+
+		writer.setHeader()
+	}
+}
+
+// timingWriter adds the Server-Timing header just before the response header is sent,
+// so phases still running, such as serialization, are measured up to that point.
+type timingWriter struct {
+	gin.ResponseWriter
+	recorder *timing.Recorder
+	done     bool
+}
+
+func (w *timingWriter) setHeader() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+	if header := w.recorder.Header(); header != "" {
+		w.Header().Set("Server-Timing", header)
+	}
+}
+
+func (w *timingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// recoverPanic answers 500 for a handler that panicked, logging the stack.
+func recoverPanic(log *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if p := recover(); p != nil {
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				log.Errorf("panic: %v\n%s", p, debug.Stack())
+				processor.RespondError(c, processor.ErrInternal, "The request handler panicked")
+			}
+		}()
+		c.Next()
+	}
+}
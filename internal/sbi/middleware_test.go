@@ -0,0 +1,62 @@
+package sbi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_RecoverPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	output := &bytes.Buffer{}
+	log := logrus.New()
+	log.SetOutput(output)
+	accessLog := sbi.NewAccessLog(logrus.NewEntry(log), &factory.AccessLog{})
+
+	router := gin.New()
+	router.Use(accessLog.Middleware(), sbi.RecoverPanic(logrus.NewEntry(log)))
+	router.GET("/panic", func(c *gin.Context) { panic("handler bug") })
+	router.GET("/abort", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	t.Run("Panic Answers Internal Error", func(t *testing.T) {
+		httpRecorder := httptest.NewRecorder()
+		router.ServeHTTP(httpRecorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		if httpRecorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, httpRecorder.Code)
+		}
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Cause != string(processor.ErrInternal) {
+			t.Errorf("Expected cause %s, got %s", processor.ErrInternal, problem.Cause)
+		}
+
+		if !strings.Contains(output.String(), "panic: handler bug") {
+			t.Errorf("Expected the panic to be logged, got log: %s", output.String())
+		}
+		if !strings.Contains(output.String(), "| 500 |") || !strings.Contains(output.String(), "/panic") {
+			t.Errorf("Expected an access log entry for the request, got log: %s", output.String())
+		}
+	})
+
+	t.Run("Abort Handler Is Panicked Again", func(t *testing.T) {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler {
+				t.Errorf("Expected panic %v, got %v", http.ErrAbortHandler, p)
+			}
+		}()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+}
@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/timing"
+	"github.com/gin-gonic/gin"
+)
+
+func (p *Processor) GetMaintenance(ctx context.Context, c *gin.Context) {
+	defer p.perf.Start("GetMaintenance")()
+	defer timing.Start(ctx, timing.PhaseProcessor)()
+
+	nfCtx, err := p.nfContext()
+	if err != nil {
+		respondContextError(c, err)
+		return
+	}
+	endStorage := timing.Start(ctx, timing.PhaseStorage)
+	maintenance := nfCtx.GetMaintenance()
+	endStorage()
+
+	defer timing.Start(ctx, timing.PhaseSerialization)()
+	c.JSON(http.StatusOK, maintenance)
+}
+
+// SetMaintenance switches maintenance mode. A zero RetryAfter keeps the currently configured value.
+func (p *Processor) SetMaintenance(ctx context.Context, c *gin.Context, maintenance nf_context.Maintenance) {
+	defer p.perf.Start("SetMaintenance")()
+	defer timing.Start(ctx, timing.PhaseProcessor)()
+
+	nfCtx, err := p.nfContext()
+	if err != nil {
+		respondContextError(c, err)
+		return
+	}
+	endStorage := timing.Start(ctx, timing.PhaseStorage)
+	if maintenance.RetryAfter == 0 {
+		maintenance.RetryAfter = nfCtx.GetMaintenance().RetryAfter
+	}
+	nfCtx.SetMaintenance(maintenance)
+	endStorage()
+	p.log.Warnf("Maintenance mode is set to [%v]: %s", maintenance.Enabled, maintenance.Message)
+
+	defer timing.Start(ctx, timing.PhaseSerialization)()
+	c.JSON(http.StatusOK, maintenance)
+}
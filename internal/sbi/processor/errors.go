@@ -0,0 +1,233 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/gin-gonic/gin"
+
+	"github.com/free5gc/openapi/models"
+)
+
+// ErrorCode identifies an error condition so clients do not have to parse messages.
+// It is sent as both the code and the cause of every error response.
+type ErrorCode string
+
+const (
+	ErrInternal              ErrorCode = "ERR_INTERNAL"
+	ErrContextNotInitialized ErrorCode = "ERR_CONTEXT_NOT_INITIALIZED"
+	ErrInvalidBody           ErrorCode = "ERR_INVALID_BODY"
+	ErrMissingParameter      ErrorCode = "ERR_MISSING_PARAMETER"
+	ErrInvalidParameter      ErrorCode = "ERR_INVALID_PARAMETER"
+	ErrInvalidHeader         ErrorCode = "ERR_INVALID_HEADER"
+	ErrInvalidQuery          ErrorCode = "ERR_INVALID_QUERY"
+	ErrRouteNotFound         ErrorCode = "ERR_ROUTE_NOT_FOUND"
+	ErrCharacterNotFound     ErrorCode = "ERR_CHARACTER_NOT_FOUND"
+	ErrTapDisabled           ErrorCode = "ERR_TAP_DISABLED"
+	ErrMaintenance           ErrorCode = "ERR_MAINTENANCE"
+	ErrRequestTimeout        ErrorCode = "ERR_REQUEST_TIMEOUT"
+	ErrRateLimited           ErrorCode = "ERR_RATE_LIMITED"
+	ErrClientClosed          ErrorCode = "ERR_CLIENT_CLOSED"
+	ErrDraining              ErrorCode = "ERR_DRAINING"
+	ErrFeatureNotFound       ErrorCode = "ERR_FEATURE_NOT_FOUND"
+	ErrFeatureDisabled       ErrorCode = "ERR_FEATURE_DISABLED"
+)
+
+// StatusClientClosedRequest is the nginx convention for a request whose client went away before
+// the response was written. The response is never read, but it shows up in access logs and metrics.
+const StatusClientClosedRequest = 499
+
+type ErrorInfo struct {
+	Code        ErrorCode `json:"code"`
+	Status      int       `json:"status"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
+
+// errorCatalogue lists every code the NF may return. A code must be added here before it is used.
+var errorCatalogue = []ErrorInfo{
+	{
+		Code:        ErrInternal,
+		Status:      http.StatusInternalServerError,
+		Title:       "Internal Server Error",
+		Description: "The NF failed unexpectedly while handling the request.",
+	},
+	{
+		Code:        ErrContextNotInitialized,
+		Status:      http.StatusInternalServerError,
+		Title:       "Internal Server Error",
+		Description: "The NF context was not initialized before requests were served.",
+	},
+	{
+		Code:        ErrInvalidBody,
+		Status:      http.StatusBadRequest,
+		Title:       "Malformed request syntax",
+		Description: "The request body is not valid JSON or misses required fields.",
+	},
+	{
+		Code:        ErrMissingParameter,
+		Status:      http.StatusBadRequest,
+		Title:       "Missing parameter",
+		Description: "A required path or query parameter is empty.",
+	},
+	{
+		Code:        ErrInvalidParameter,
+		Status:      http.StatusBadRequest,
+		Title:       "Invalid parameter",
+		Description: "A path parameter is not valid UTF-8 or contains control characters.",
+	},
+	{
+		Code:        ErrInvalidHeader,
+		Status:      http.StatusBadRequest,
+		Title:       "Invalid header",
+		Description: "A request header has a value the NF does not accept.",
+	},
+	{
+		Code:        ErrInvalidQuery,
+		Status:      http.StatusBadRequest,
+		Title:       "Invalid query",
+		Description: "A query parameter has a value the endpoint does not accept.",
+	},
+	{
+		Code:        ErrTapDisabled,
+		Status:      http.StatusNotFound,
+		Title:       "Tap disabled",
+		Description: "The debug tap is disabled in the configuration.",
+	},
+	{
+		Code:        ErrRouteNotFound,
+		Status:      http.StatusNotFound,
+		Title:       "Not Found",
+		Description: "No route matches the request method and path.",
+	},
+	{
+		Code:        ErrCharacterNotFound,
+		Status:      http.StatusNotFound,
+		Title:       "Character not found",
+		Description: "The requested SPYxFAMILY character does not exist.",
+	},
+	{
+		Code:        ErrFeatureNotFound,
+		Status:      http.StatusNotFound,
+		Title:       "Feature not found",
+		Description: "The feature flag is neither configured nor guarding any route.",
+	},
+	{
+		Code:        ErrFeatureDisabled,
+		Status:      http.StatusNotImplemented,
+		Title:       "Not Implemented",
+		Description: "The route belongs to a feature that is disabled on this NF.",
+	},
+	{
+		Code:        ErrMaintenance,
+		Status:      http.StatusServiceUnavailable,
+		Title:       "Service Unavailable",
+		Description: "The NF is in maintenance mode and rejects mutating requests. Retry after the Retry-After header.",
+	},
+	{
+		Code:        ErrRateLimited,
+		Status:      http.StatusServiceUnavailable,
+		Title:       "Service Unavailable",
+		Description: "Too many requests are being processed. Retry after the Retry-After header.",
+	},
+	{
+		Code:        ErrDraining,
+		Status:      http.StatusServiceUnavailable,
+		Title:       "Service Unavailable",
+		Description: "The NF is draining before it is taken out of service. Retry after the Retry-After header, ideally on another instance.",
+	},
+	{
+		Code:        ErrRequestTimeout,
+		Status:      http.StatusGatewayTimeout,
+		Title:       "Gateway Timeout",
+		Description: "The deadline set by X-Request-Timeout passed before the request was processed.",
+	},
+	{
+		Code:        ErrClientClosed,
+		Status:      StatusClientClosedRequest,
+		Title:       "Client Closed Request",
+		Description: "The client cancelled the request before it was processed.",
+	},
+}
+
+// ErrorCatalogue returns every error code with its status and description.
+func ErrorCatalogue() []ErrorInfo {
+	catalogue := make([]ErrorInfo, len(errorCatalogue))
+	copy(catalogue, errorCatalogue)
+	return catalogue
+}
+
+// LookupError returns the catalogue entry of a code.
+func LookupError(code ErrorCode) (ErrorInfo, bool) {
+	for _, info := range errorCatalogue {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return ErrorInfo{}, false
+}
+
+// ProblemDetails is the body of every error response: the standard problem details
+// with the error code repeated in its own field.
+type ProblemDetails struct {
+	models.ProblemDetails
+	Code ErrorCode `json:"code"`
+}
+
+// NewProblemDetails builds the error body for a catalogued code. Unknown codes are reported as ErrInternal.
+func NewProblemDetails(code ErrorCode, detail string) ProblemDetails {
+	info, ok := LookupError(code)
+	if !ok {
+		logger.ProcLog.Errorf("Error code [%s] is not in the catalogue", code)
+		info, _ = LookupError(ErrInternal)
+	}
+	return ProblemDetails{
+		ProblemDetails: models.ProblemDetails{
+			Title:  info.Title,
+			Status: int32(info.Status),
+			Detail: detail,
+			Cause:  string(info.Code),
+		},
+		Code: info.Code,
+	}
+}
+
+// RespondError aborts the request with the problem details of a catalogued code.
+func RespondError(c *gin.Context, code ErrorCode, detail string) {
+	RespondInvalidParams(c, code, detail, nil)
+}
+
+// RespondInvalidParams is RespondError naming the offending parameters in invalidParams.
+// The title is translated to the language asked for by Accept-Language; the code and detail are not.
+func RespondInvalidParams(c *gin.Context, code ErrorCode, detail string, params []models.InvalidParam) {
+	problem := NewProblemDetails(code, detail)
+	problem.InvalidParams = params
+
+	info, _ := LookupError(problem.Code)
+	title, lang := LocalizedTitle(c.GetHeader("Accept-Language"), info)
+	problem.Title = title
+	c.Header("Content-Language", lang)
+	c.AbortWithStatusJSON(int(problem.Status), problem)
+}
+
+// RespondIfDone answers once ctx is done so slow paths can stop before doing more work:
+// 504 when its deadline passed and 499 when the client went away.
+func RespondIfDone(ctx context.Context, c *gin.Context) bool {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.DeadlineExceeded):
+		RespondError(c, ErrRequestTimeout, "Request deadline exceeded")
+	default:
+		RespondError(c, ErrClientClosed, "Request cancelled by the client")
+	}
+	return true
+}
+
+func (p *Processor) ListErrorCodes(ctx context.Context, c *gin.Context) {
+	defer p.perf.Start("ListErrorCodes")()
+	c.JSON(http.StatusOK, ErrorCatalogue())
+}
@@ -0,0 +1,109 @@
+package processor_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+)
+
+func Test_ErrorCatalogue(t *testing.T) {
+	seen := map[processor.ErrorCode]bool{}
+	for _, info := range processor.ErrorCatalogue() {
+		if seen[info.Code] {
+			t.Errorf("Duplicated error code %s", info.Code)
+		}
+		seen[info.Code] = true
+
+		if !strings.HasPrefix(string(info.Code), "ERR_") {
+			t.Errorf("Expected error code %s to start with ERR_", info.Code)
+		}
+		if info.Status < http.StatusBadRequest || info.Title == "" || info.Description == "" {
+			t.Errorf("Incomplete catalogue entry: %+v", info)
+		}
+	}
+
+	t.Run("Unknown Code Falls Back To Internal", func(t *testing.T) {
+		httpRecorder, ginCtx := testutil.NewGinContext(t, http.MethodGet, "/", nil)
+		processor.RespondError(ginCtx, "ERR_NOT_CATALOGUED", "boom")
+
+		if httpRecorder.Code != http.StatusInternalServerError {
+			t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, httpRecorder.Code)
+		}
+		var problem processor.ProblemDetails
+		if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Code != processor.ErrInternal || problem.Cause != string(processor.ErrInternal) {
+			t.Errorf("Expected code %s, got %+v", processor.ErrInternal, problem)
+		}
+	})
+}
+
+// successStatuses may be written directly; every other status must go through RespondError.
+var successStatuses = map[string]bool{
+	"StatusOK":        true,
+	"StatusCreated":   true,
+	"StatusAccepted":  true,
+	"StatusNoContent": true,
+}
+
+// Test_ErrorPathsUseCatalogue walks the SBI sources and fails on error responses written without a code.
+func Test_ErrorPathsUseCatalogue(t *testing.T) {
+	writers := map[string]bool{
+		"String":              true,
+		"JSON":                true,
+		"Data":                true,
+		"Status":              true,
+		"AbortWithStatus":     true,
+		"AbortWithStatusJSON": true,
+	}
+
+	files, err := filepath.Glob("../*.go")
+	if err != nil {
+		t.Fatalf("Failed to list sources: %s", err)
+	}
+	own, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("Failed to list sources: %s", err)
+	}
+	files = append(files, own...)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "_mock.go") {
+			continue
+		}
+		f, parseErr := parser.ParseFile(fset, file, nil, 0)
+		if parseErr != nil {
+			t.Fatalf("Failed to parse %s: %s", file, parseErr)
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			fun, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || !writers[fun.Sel.Name] {
+				return true
+			}
+			status, ok := call.Args[0].(*ast.SelectorExpr)
+			if !ok || !strings.HasPrefix(status.Sel.Name, "Status") {
+				return true
+			}
+			if !successStatuses[status.Sel.Name] {
+				t.Errorf("%s: %s written without an error code, use processor.RespondError",
+					fset.Position(call.Pos()), status.Sel.Name)
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"golang.org/x/text/language"
+)
+
+// locales holds the translated error titles, one <language tag>.json file per language,
+// mapping error codes to titles. English is the error catalogue itself.
+//
+//go:embed locales/*.json
+var localeFS embed.FS
+
+type locale struct {
+	tag    language.Tag
+	titles map[ErrorCode]string
+}
+
+var (
+	locales       = loadLocales()
+	localeMatcher = newLocaleMatcher(locales)
+)
+
+func loadLocales() []locale {
+	var loaded []locale
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		logger.ProcLog.Errorf("Read locales failed: %+v", err)
+		return nil
+	}
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			logger.ProcLog.Errorf("Locale [%s] is not a language tag: %+v", file.Name(), err)
+			continue
+		}
+		content, err := localeFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			logger.ProcLog.Errorf("Read locale [%s] failed: %+v", file.Name(), err)
+			continue
+		}
+		titles := map[ErrorCode]string{}
+		if err = json.Unmarshal(content, &titles); err != nil {
+			logger.ProcLog.Errorf("Parse locale [%s] failed: %+v", file.Name(), err)
+			continue
+		}
+		loaded = append(loaded, locale{tag: tag, titles: titles})
+	}
+	return loaded
+}
+
+// newLocaleMatcher lists English first so it is picked when nothing else matches.
+// Any other match at index i is locales[i-1].
+func newLocaleMatcher(locales []locale) language.Matcher {
+	tags := []language.Tag{language.English}
+	for _, l := range locales {
+		tags = append(tags, l.tag)
+	}
+	return language.NewMatcher(tags)
+}
+
+// Locales returns the language tags error titles are translated to, besides English.
+func Locales() []string {
+	tags := make([]string, 0, len(locales))
+	for _, l := range locales {
+		tags = append(tags, l.tag.String())
+	}
+	return tags
+}
+
+// LocalizedTitle returns the title of info in the language preferred by an Accept-Language header,
+// and that language. Codes without a translation fall back to the English title.
+func LocalizedTitle(acceptLanguage string, info ErrorInfo) (string, string) {
+	preferred, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(preferred) == 0 {
+		return info.Title, language.English.String()
+	}
+	_, index, confidence := localeMatcher.Match(preferred...)
+	if index == 0 || confidence == language.No {
+		return info.Title, language.English.String()
+	}
+
+	l := locales[index-1]
+	if title, ok := l.titles[info.Code]; ok {
+		return title, l.tag.String()
+	}
+	logger.ProcLog.Warnf("Error code [%s] has no %s title, falling back to English", info.Code, l.tag)
+	return info.Title, language.English.String()
+}
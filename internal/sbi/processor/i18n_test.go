@@ -0,0 +1,67 @@
+package processor_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+)
+
+func Test_LocalizedErrors(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+	info, _ := processor.LookupError(processor.ErrCharacterNotFound)
+
+	testCases := []struct {
+		name             string
+		acceptLanguage   string
+		expectedTitle    string
+		expectedLanguage string
+	}{
+		{"No Header", "", info.Title, "en"},
+		{"English", "en-US", info.Title, "en"},
+		{"Traditional Chinese", "zh-TW", "找不到角色", "zh-TW"},
+		{"Quality Values", "en;q=0.5, zh-TW;q=0.9", "找不到角色", "zh-TW"},
+		{"Base Language", "zh", "找不到角色", "zh-TW"},
+		{"Unsupported Language", "fr-FR, de;q=0.8", info.Title, "en"},
+		{"Malformed Header", ";;q=x", info.Title, "en"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.acceptLanguage != "" {
+				header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			resp, body := server.DoWithHeaders(t, http.MethodGet, "/spyfamily/character/Andy", nil, header)
+
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(body, &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Title != tc.expectedTitle {
+				t.Errorf("Expected title %s, got %s", tc.expectedTitle, problem.Title)
+			}
+			if lang := resp.Header.Get("Content-Language"); lang != tc.expectedLanguage {
+				t.Errorf("Expected Content-Language %s, got %s", tc.expectedLanguage, lang)
+			}
+			if problem.Code != processor.ErrCharacterNotFound || problem.Cause != string(processor.ErrCharacterNotFound) {
+				t.Errorf("Expected code %s, got %+v", processor.ErrCharacterNotFound, problem)
+			}
+			if problem.Detail != "[Andy] not found in SPYxFAMILY" {
+				t.Errorf("Expected untranslated detail, got %s", problem.Detail)
+			}
+		})
+	}
+}
+
+func Test_LocalesCoverCatalogue(t *testing.T) {
+	for _, lang := range processor.Locales() {
+		for _, info := range processor.ErrorCatalogue() {
+			if title, _ := processor.LocalizedTitle(lang, info); title == info.Title {
+				t.Errorf("Expected a %s title for %s", lang, info.Code)
+			}
+		}
+	}
+}
@@ -1,6 +1,15 @@
 package processor
 
-import "github.com/Alonza0314/nf-example/pkg/app"
+import (
+	"errors"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/perf"
+	"github.com/Alonza0314/nf-example/pkg/app"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
 
 type ProcessorNf interface {
 	app.App
@@ -10,11 +19,42 @@ type ProcessorNf interface {
 
 type Processor struct {
 	ProcessorNf
+
+	log  *logrus.Entry
+	perf *perf.Recorder
 }
 
 func NewProcessor(nf ProcessorNf) (*Processor, error) {
 	p := &Processor{
 		ProcessorNf: nf,
+		log:         logger.ProcLog,
+		perf:        perf.NewRecorder(perf.DefaultWindow),
 	}
 	return p, nil
 }
+
+// Perf returns the recent timings of the processor operations.
+func (p *Processor) Perf() *perf.Recorder {
+	return p.perf
+}
+
+// nfContext returns the NF context, or ErrContextNotInitialized instead of a context that would panic.
+func (p *Processor) nfContext() (*nf_context.NFContext, error) {
+	nfCtx := p.Context()
+	if err := nfCtx.CheckInitialized(); err != nil {
+		return nil, err
+	}
+	return nfCtx, nil
+}
+
+// respondContextError maps the sentinel errors of the NF context to problem details.
+func respondContextError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, nf_context.ErrCharacterNotFound):
+		RespondError(c, ErrCharacterNotFound, err.Error())
+	case errors.Is(err, nf_context.ErrContextNotInitialized):
+		RespondError(c, ErrContextNotInitialized, err.Error())
+	default:
+		RespondError(c, ErrInternal, err.Error())
+	}
+}
@@ -119,9 +119,11 @@ func (mr *MockProcessorNfMockRecorder) SetReportCaller(reportCaller any) *gomock
 }
 
 // Start mocks base method.
-func (m *MockProcessorNf) Start() {
+func (m *MockProcessorNf) Start() error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Start indicates an expected call of Start.
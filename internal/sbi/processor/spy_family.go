@@ -1,16 +1,66 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
+	"github.com/Alonza0314/nf-example/internal/timing"
+	"github.com/Alonza0314/nf-example/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (p *Processor) FindSpyFamilyCharacterName(c *gin.Context, targetName string) {
-	if lastName, ok := p.Context().SpyFamilyData[targetName]; ok {
-		c.String(http.StatusOK, fmt.Sprintf("Character: %s %s", targetName, lastName))
+func (p *Processor) FindSpyFamilyCharacterName(ctx context.Context, c *gin.Context, targetName string) {
+	defer p.perf.Start("FindSpyFamilyCharacterName")()
+	ctx, span := tracing.Start(ctx, "FindSpyFamilyCharacterName",
+		attribute.String("spyfamily.character.name", targetName))
+	defer span.End()
+	defer timing.Start(ctx, timing.PhaseProcessor)()
+
+	p.log.WithField("name", targetName).Debug("Find SPYxFAMILY character")
+	if RespondIfDone(ctx, c) {
+		span.SetAttributes(tracing.AttrHTTPStatusCode.Int(c.Writer.Status()))
+		return
+	}
+
+	endStorage := timing.Start(ctx, timing.PhaseStorage)
+	lastName, err := p.Context().LookupCharacter(targetName)
+	endStorage()
+	if err != nil {
+		respondContextError(c, err)
+		span.SetAttributes(tracing.AttrHTTPStatusCode.Int(c.Writer.Status()))
+		return
+	}
+
+	span.SetAttributes(tracing.AttrHTTPStatusCode.Int(http.StatusOK))
+	defer timing.Start(ctx, timing.PhaseSerialization)()
+	c.String(http.StatusOK, fmt.Sprintf("Character: %s %s", targetName, lastName))
+}
+
+func (p *Processor) SearchSpyFamilyCharacters(ctx context.Context, c *gin.Context, lastName string) {
+	defer p.perf.Start("SearchSpyFamilyCharacters")()
+	ctx, span := tracing.Start(ctx, "SearchSpyFamilyCharacters",
+		attribute.String("spyfamily.character.last_name", lastName))
+	defer span.End()
+	defer timing.Start(ctx, timing.PhaseProcessor)()
+
+	p.log.WithField("lastName", lastName).Debug("Search SPYxFAMILY characters")
+	if RespondIfDone(ctx, c) {
+		span.SetAttributes(tracing.AttrHTTPStatusCode.Int(c.Writer.Status()))
 		return
 	}
-	c.String(http.StatusNotFound, fmt.Sprintf("[%s] not found in SPYxFAMILY", targetName))
+
+	endStorage := timing.Start(ctx, timing.PhaseStorage)
+	firstNames, err := p.Context().SearchCharacters(lastName)
+	endStorage()
+	if err != nil {
+		respondContextError(c, err)
+		span.SetAttributes(tracing.AttrHTTPStatusCode.Int(c.Writer.Status()))
+		return
+	}
+
+	span.SetAttributes(tracing.AttrHTTPStatusCode.Int(http.StatusOK))
+	defer timing.Start(ctx, timing.PhaseSerialization)()
+	c.JSON(http.StatusOK, firstNames)
 }
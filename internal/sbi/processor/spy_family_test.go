@@ -1,71 +1,152 @@
 package processor_test
 
 import (
-	"net/http/httptest"
+	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
 	nf_context "github.com/Alonza0314/nf-example/internal/context"
 	"github.com/Alonza0314/nf-example/internal/sbi/processor"
-	"github.com/gin-gonic/gin"
-	gomock "go.uber.org/mock/gomock"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"go.uber.org/mock/gomock"
 )
 
 func Test_FindSpyFamilyCharacterName(t *testing.T) {
-	gin.SetMode(gin.TestMode)
+	nfCtx := &nf_context.NFContext{
+		SpyFamilyData: map[string]string{
+			"Anya": "Forger",
+		},
+	}
+	seeded := testutil.SeedCharacters(nfCtx, 3)
+	p := testutil.NewTestServer(t, testutil.Options{Context: nfCtx}).Processor
 
-	mockCtrl := gomock.NewController(t)
-	processorNf := processor.NewMockProcessorNf(mockCtrl)
-	processor, err := processor.NewProcessor(processorNf)
-	if err != nil {
-		t.Errorf("Failed to create processor: %s", err)
-		return
+	testCases := []struct {
+		name           string
+		inputName      string
+		expectedStatus int
+		expectedBody   string
+		expectedCode   processor.ErrorCode
+	}{
+		{
+			name:           "Find Character That Exists",
+			inputName:      "Anya",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Character: Anya Forger",
+		},
+		{
+			name:           "Find Seeded Character",
+			inputName:      seeded[1],
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Character: Agent002 Family002",
+		},
+		{
+			name:           "Find Character That Does Not Exist",
+			inputName:      "Andy",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "[Andy] not found in SPYxFAMILY",
+			expectedCode:   processor.ErrCharacterNotFound,
+		},
 	}
 
-	t.Run("Find Character That Exists", func(t *testing.T) {
-		const INPUT_NAME = "Anya"
-		const EXPECTED_STATUS = 200
-		const EXPECTED_BODY = "Character: " + INPUT_NAME + " Forger"
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRecorder, ginCtx := testutil.NewGinContext(t, http.MethodGet, "/", nil)
+			p.FindSpyFamilyCharacterName(ginCtx.Request.Context(), ginCtx, tc.inputName)
+
+			if httpRecorder.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, httpRecorder.Code)
+			}
 
-		processorNf.EXPECT().Context().Return(&nf_context.NFContext{
-			SpyFamilyData: map[string]string{
-				"Anya": "Forger",
-			},
+			if tc.expectedCode == "" {
+				if httpRecorder.Body.String() != tc.expectedBody {
+					t.Errorf("Expected body %s, got %s", tc.expectedBody, httpRecorder.Body.String())
+				}
+				return
+			}
+
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Code != tc.expectedCode || problem.Cause != string(tc.expectedCode) {
+				t.Errorf("Expected code %s, got %+v", tc.expectedCode, problem)
+			}
+			if problem.Detail != tc.expectedBody {
+				t.Errorf("Expected detail %s, got %s", tc.expectedBody, problem.Detail)
+			}
 		})
+	}
+}
 
-		httpRecorder := httptest.NewRecorder()
-		ginCtx, _ := gin.CreateTestContext(httpRecorder)
-		processor.FindSpyFamilyCharacterName(ginCtx, INPUT_NAME)
+func Test_FindSpyFamilyCharacterNameDoneBeforeLookup(t *testing.T) {
+	p := testutil.NewTestServer(t, testutil.Options{}).Processor
 
-		if httpRecorder.Code != EXPECTED_STATUS {
-			t.Errorf("Expected status code %d, got %d", EXPECTED_STATUS, httpRecorder.Code)
-		}
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
 
-		if httpRecorder.Body.String() != EXPECTED_BODY {
-			t.Errorf("Expected body %s, got %s", EXPECTED_BODY, httpRecorder.Body.String())
-		}
-	})
+	testCases := []struct {
+		name           string
+		ctx            context.Context
+		expectedStatus int
+		expectedCode   processor.ErrorCode
+	}{
+		{
+			name:           "Deadline Exceeded",
+			ctx:            expired,
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   processor.ErrRequestTimeout,
+		},
+		{
+			name:           "Cancelled By Client",
+			ctx:            cancelled,
+			expectedStatus: processor.StatusClientClosedRequest,
+			expectedCode:   processor.ErrClientClosed,
+		},
+	}
 
-	t.Run("Find Character That Does Not Exist", func(t *testing.T) {
-		const INPUT_NAME = "Andy"
-		const EXPECTED_STATUS = 404
-		const EXPECTED_BODY = "[" + INPUT_NAME + "] not found in SPYxFAMILY"
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRecorder, ginCtx := testutil.NewGinContext(t, http.MethodGet, "/", nil)
+			p.FindSpyFamilyCharacterName(tc.ctx, ginCtx, "Anya")
 
-		processorNf.EXPECT().Context().Return(&nf_context.NFContext{
-			SpyFamilyData: map[string]string{
-				"Anya": "Forger",
-			},
+			if httpRecorder.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, httpRecorder.Code)
+			}
+			var problem processor.ProblemDetails
+			if err := json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to unmarshal body: %s", err)
+			}
+			if problem.Code != tc.expectedCode {
+				t.Errorf("Expected code %s, got %s", tc.expectedCode, problem.Code)
+			}
 		})
+	}
+}
 
-		httpRecorder := httptest.NewRecorder()
-		ginCtx, _ := gin.CreateTestContext(httpRecorder)
-		processor.FindSpyFamilyCharacterName(ginCtx, INPUT_NAME)
+func Test_FindSpyFamilyCharacterNameUninitializedContext(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	nfApp := processor.NewMockProcessorNf(mockCtrl)
+	nfApp.EXPECT().Context().Return(nil).AnyTimes()
+	p, err := processor.NewProcessor(nfApp)
+	if err != nil {
+		t.Fatalf("Failed to create processor: %s", err)
+	}
 
-		if httpRecorder.Code != EXPECTED_STATUS {
-			t.Errorf("Expected status code %d, got %d", EXPECTED_STATUS, httpRecorder.Code)
-		}
+	httpRecorder, ginCtx := testutil.NewGinContext(t, http.MethodGet, "/", nil)
+	p.FindSpyFamilyCharacterName(ginCtx.Request.Context(), ginCtx, "Anya")
 
-		if httpRecorder.Body.String() != EXPECTED_BODY {
-			t.Errorf("Expected body %s, got %s", EXPECTED_BODY, httpRecorder.Body.String())
-		}
-	})
+	if httpRecorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, httpRecorder.Code)
+	}
+	var problem processor.ProblemDetails
+	if err = json.Unmarshal(httpRecorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if problem.Code != processor.ErrContextNotInitialized {
+		t.Errorf("Expected code %s, got %s", processor.ErrContextNotInitialized, problem.Code)
+	}
 }
@@ -0,0 +1,201 @@
+package sbi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Module is a set of routes mounted under a common path prefix, e.g. "/spyfamily".
+type Module interface {
+	Name() string
+	Prefix() string
+	Routes() []Route
+}
+
+type routeModule struct {
+	name   string
+	prefix string
+	routes []Route
+}
+
+func (m *routeModule) Name() string    { return m.name }
+func (m *routeModule) Prefix() string  { return m.prefix }
+func (m *routeModule) Routes() []Route { return m.routes }
+
+// NewModule bundles routes into a Module.
+func NewModule(name, prefix string, routes []Route) Module {
+	return &routeModule{
+		name:   name,
+		prefix: prefix,
+		routes: routes,
+	}
+}
+
+// RegisteredRoute describes a route accepted by the registry.
+type RegisteredRoute struct {
+	Module  string `json:"module"`
+	Name    string `json:"name"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Feature string `json:"feature,omitempty"`
+}
+
+// RouteRegistry collects the routes of every module and rejects any two routes
+// that gin would treat as the same method and path.
+//
+// Every route is served both with and without a trailing slash, so "/spyfamily" and "/spyfamily/"
+// reach the same handler without a redirect. Literal segments and path parameters stay case-sensitive.
+type RouteRegistry struct {
+	modules []Module
+	routes  []RegisteredRoute
+	// owners maps a method and normalized path to the route which claimed it.
+	owners map[string]RegisteredRoute
+}
+
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{
+		owners: make(map[string]RegisteredRoute),
+	}
+}
+
+// Register adds all routes of a module. Nothing is registered if any route conflicts.
+func (r *RouteRegistry) Register(m Module) error {
+	accepted := make([]RegisteredRoute, 0, len(m.Routes()))
+	claimed := make(map[string]RegisteredRoute)
+	for _, route := range m.Routes() {
+		if !isSupportedMethod(route.Method) {
+			return fmt.Errorf("module [%s] route [%s]: unsupported method %q", m.Name(), route.Name, route.Method)
+		}
+
+		registered := RegisteredRoute{
+			Module:  m.Name(),
+			Name:    route.Name,
+			Method:  route.Method,
+			Path:    joinPath(m.Prefix(), route.Pattern),
+			Feature: route.Feature,
+		}
+		key := registered.Method + " " + normalizePath(registered.Path)
+
+		owner, ok := r.owners[key]
+		if !ok {
+			owner, ok = claimed[key]
+		}
+		if ok {
+			return fmt.Errorf("module [%s] route [%s] %s %s conflicts with module [%s] route [%s] %s %s",
+				registered.Module, registered.Name, registered.Method, registered.Path,
+				owner.Module, owner.Name, owner.Method, owner.Path)
+		}
+		claimed[key] = registered
+		accepted = append(accepted, registered)
+	}
+
+	for key, registered := range claimed {
+		r.owners[key] = registered
+	}
+	r.routes = append(r.routes, accepted...)
+	r.modules = append(r.modules, m)
+	return nil
+}
+
+// Apply mounts every registered module on the router, under both slash forms of each path.
+// Routes flagged with a feature run the guard first, which aborts the request while it is disabled.
+func (r *RouteRegistry) Apply(router *gin.Engine, guard func(feature string) gin.HandlerFunc) {
+	router.RedirectTrailingSlash = false
+	router.RedirectFixedPath = false
+
+	for _, m := range r.modules {
+		routes := make([]Route, 0, 2*len(m.Routes()))
+		for _, route := range m.Routes() {
+			route.Pattern = joinPath(m.Prefix(), route.Pattern)
+			if route.Feature != "" && guard != nil {
+				route.APIFunc = guarded(guard(route.Feature), route.APIFunc)
+			}
+			routes = append(routes, route)
+			if alternate, ok := toggleTrailingSlash(route.Pattern); ok {
+				route.Pattern = alternate
+				routes = append(routes, route)
+			}
+		}
+		applyRoutes(&router.RouterGroup, routes)
+	}
+}
+
+// Routes returns the registered routes sorted by path and method.
+func (r *RouteRegistry) Routes() []RegisteredRoute {
+	routes := make([]RegisteredRoute, len(r.routes))
+	copy(routes, r.routes)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Features returns the feature flags annotated on routes, each with the routes it guards.
+func (r *RouteRegistry) Features() map[string][]RegisteredRoute {
+	features := map[string][]RegisteredRoute{}
+	for _, route := range r.Routes() {
+		if route.Feature != "" {
+			features[route.Feature] = append(features[route.Feature], route)
+		}
+	}
+	return features
+}
+
+func guarded(guard, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		guard(c)
+		if c.IsAborted() {
+			return
+		}
+		handler(c)
+	}
+}
+
+func isSupportedMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func joinPath(prefix, pattern string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(pattern, "/")
+}
+
+// toggleTrailingSlash returns the other slash form of a path. The root and catch-all paths have none.
+func toggleTrailingSlash(path string) (string, bool) {
+	if path == "/" || strings.Contains(path, "*") {
+		return "", false
+	}
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/"), true
+	}
+	return path + "/", true
+}
+
+// normalizePath replaces parameter names so "/character/:Name" and "/character/:ID" compare equal,
+// matching how gin's router sees them. The trailing slash is dropped since both forms are served.
+func normalizePath(path string) string {
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			segments[i] = ":"
+		case strings.HasPrefix(segment, "*"):
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
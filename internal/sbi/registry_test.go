@@ -0,0 +1,205 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func fakeRoute(method, pattern string) sbi.Route {
+	return sbi.Route{
+		Name:    "Fake " + method + " " + pattern,
+		Method:  method,
+		Pattern: pattern,
+		APIFunc: func(c *gin.Context) {},
+	}
+}
+
+func Test_RouteRegistry(t *testing.T) {
+	testCases := []struct {
+		name          string
+		modules       []sbi.Module
+		expectedError string
+	}{
+		{
+			name: "Same Path Different Methods",
+			modules: []sbi.Module{
+				sbi.NewModule("message", "/message", []sbi.Route{
+					fakeRoute(http.MethodGet, "/:ID"),
+					fakeRoute(http.MethodDelete, "/:ID"),
+				}),
+			},
+		},
+		{
+			name: "Duplicate Method And Pattern Across Modules",
+			modules: []sbi.Module{
+				sbi.NewModule("spyfamily", "/spyfamily", []sbi.Route{fakeRoute(http.MethodGet, "/character/:Name")}),
+				sbi.NewModule("fake", "/spyfamily/character", []sbi.Route{fakeRoute(http.MethodGet, "/:Name")}),
+			},
+			expectedError: "module [fake] route [Fake GET /:Name] GET /spyfamily/character/:Name conflicts with " +
+				"module [spyfamily] route [Fake GET /character/:Name] GET /spyfamily/character/:Name",
+		},
+		{
+			name: "Different Parameter Names Conflict",
+			modules: []sbi.Module{
+				sbi.NewModule("spyfamily", "/spyfamily", []sbi.Route{fakeRoute(http.MethodGet, "/character/:Name")}),
+				sbi.NewModule("fake", "/spyfamily", []sbi.Route{fakeRoute(http.MethodGet, "/character/:ID")}),
+			},
+			expectedError: "conflicts with module [spyfamily]",
+		},
+		{
+			name: "Duplicate Within A Module",
+			modules: []sbi.Module{
+				sbi.NewModule("fake", "/fake", []sbi.Route{
+					fakeRoute(http.MethodPost, "/"),
+					fakeRoute(http.MethodPost, "/"),
+				}),
+			},
+			expectedError: "module [fake] route [Fake POST /] POST /fake/ conflicts with module [fake]",
+		},
+		{
+			name: "Trailing Slash Variants Conflict",
+			modules: []sbi.Module{
+				sbi.NewModule("fake", "/fake", []sbi.Route{
+					fakeRoute(http.MethodGet, "/list"),
+					fakeRoute(http.MethodGet, "/list/"),
+				}),
+			},
+			expectedError: "module [fake] route [Fake GET /list/] GET /fake/list/ conflicts with module [fake]",
+		},
+		{
+			name: "Unsupported Method",
+			modules: []sbi.Module{
+				sbi.NewModule("fake", "/fake", []sbi.Route{fakeRoute("TRACE", "/")}),
+			},
+			expectedError: `module [fake] route [Fake TRACE /]: unsupported method "TRACE"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := sbi.NewRouteRegistry()
+
+			var err error
+			for _, m := range tc.modules {
+				if err = registry.Register(m); err != nil {
+					break
+				}
+			}
+
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+
+	t.Run("Failed Module Is Not Registered", func(t *testing.T) {
+		registry := sbi.NewRouteRegistry()
+		if err := registry.Register(sbi.NewModule("fake", "/fake", []sbi.Route{
+			fakeRoute(http.MethodGet, "/"),
+			fakeRoute(http.MethodGet, "/"),
+		})); err == nil {
+			t.Fatalf("Expected conflict error")
+		}
+		if routes := registry.Routes(); len(routes) != 0 {
+			t.Errorf("Expected no registered routes, got %v", routes)
+		}
+	})
+}
+
+// enableRouteFeatures turns on the feature of every flagged route, so all of them are served.
+func enableRouteFeatures(server *testutil.TestServer, routes []sbi.RegisteredRoute) {
+	for _, route := range routes {
+		if route.Feature != "" {
+			server.Context.SetFeature(route.Feature, true)
+		}
+	}
+}
+
+func Test_HTTPGetRoutes(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	status, body := server.Do(t, http.MethodGet, "/admin/routes", nil)
+	if status != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+
+	var routes []sbi.RegisteredRoute
+	if err := json.Unmarshal(body, &routes); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	enableRouteFeatures(server, routes)
+	for _, route := range routes {
+		if route.Method != http.MethodGet {
+			continue
+		}
+		if strings.Contains(route.Path, ":") {
+			continue
+		}
+		var problem processor.ProblemDetails
+		if _, routeBody := server.Do(t, route.Method, route.Path, nil); json.Unmarshal(routeBody, &problem) == nil &&
+			problem.Code == processor.ErrRouteNotFound {
+			t.Errorf("Listed route %s %s is not served", route.Method, route.Path)
+		}
+	}
+
+	testutil.AssertJSONGolden(t, body, "testdata/admin_routes.golden.json")
+}
+
+func Test_TrailingSlash(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	status, body := server.Do(t, http.MethodGet, "/admin/routes", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, status)
+	}
+	var routes []sbi.RegisteredRoute
+	if err := json.Unmarshal(body, &routes); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	enableRouteFeatures(server, routes)
+
+	for _, route := range routes {
+		path := strings.ReplaceAll(route.Path, ":Name", "Anya")
+		alternate := path + "/"
+		if strings.HasSuffix(path, "/") {
+			alternate = strings.TrimSuffix(path, "/")
+		}
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			status, body := server.Do(t, route.Method, path, nil)
+			alternateStatus, alternateBody := server.Do(t, route.Method, alternate, nil)
+
+			if alternateStatus != status {
+				t.Errorf("Expected status code %d for %s, got %d", status, alternate, alternateStatus)
+			}
+			// The metrics body records the request just made, so it never repeats.
+			if path != "/metrics" && string(alternateBody) != string(body) {
+				t.Errorf("Expected body %s for %s, got %s", body, alternate, alternateBody)
+			}
+		})
+	}
+
+	t.Run("Literals Are Case-Sensitive", func(t *testing.T) {
+		var problem processor.ProblemDetails
+		_, body := server.Do(t, http.MethodGet, "/SPYFAMILY/", nil)
+		if err := json.Unmarshal(body, &problem); err != nil {
+			t.Fatalf("Failed to unmarshal body: %s", err)
+		}
+		if problem.Code != processor.ErrRouteNotFound {
+			t.Errorf("Expected code %s, got %s", processor.ErrRouteNotFound, problem.Code)
+		}
+	})
+}
@@ -5,11 +5,12 @@ import (
 	"net/http"
 
 	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/tracing"
 	"github.com/Alonza0314/nf-example/pkg/app"
 	"github.com/gin-gonic/gin"
 
 	"github.com/free5gc/util/httpwrapper"
-	logger_util "github.com/free5gc/util/logger"
 )
 
 type Route struct {
@@ -17,6 +18,8 @@ type Route struct {
 	Method  string
 	Pattern string
 	APIFunc gin.HandlerFunc
+	// Feature, when set, serves the route only while that feature flag is enabled.
+	Feature string
 }
 
 func applyRoutes(group *gin.RouterGroup, routes []Route) {
@@ -36,16 +39,70 @@ func applyRoutes(group *gin.RouterGroup, routes []Route) {
 	}
 }
 
-func newRouter(s *Server) *gin.Engine {
-	router := logger_util.NewGinWithLogrus(logger.GinLog)
+// modules lists every route module served by the NF. New modules are added here.
+func (s *Server) modules() []Module {
+	return []Module{
+		NewModule("default", "/default", s.getDefaultRoute()),
+		NewModule("spyfamily", "/spyfamily", s.getSpyFamilyRoute()),
+		NewModule("admin", "/admin", s.getAdminRoute()),
+		NewModule("observability", "", s.getObservabilityRoute()),
+	}
+}
+
+func newRouter(s *Server) (*gin.Engine, error) {
+	middlewares := []gin.HandlerFunc{s.countInflight(), s.accessLog.Middleware()}
+	if s.tap != nil {
+		middlewares = append(middlewares, s.tap.Middleware())
+	}
+	middlewares = append(middlewares, s.drainer.Middleware(), s.limiter.Middleware(), requestDeadline(s.maxRequestTimeout()),
+		tracing.Middleware(), observeDuration(s.duration), serverTiming(s.Config().Configuration.Sbi.ServerTiming))
+	router := gin.New()
+	// Match routes on the escaped path and unescape parameters afterwards, so a parameter
+	// holding an encoded slash such as "AC%2FDC" stays one segment.
+	router.UseRawPath = true
+	router.UnescapePathValues = true
+	router.Use(middlewares...)
+	router.Use(recoverPanic(logger.GinLog))
+	applyTrustedProxies(router, s.Config().Configuration.Sbi.TrustedProxies)
+	router.Use(s.maintenanceGuard())
+
+	s.routes = NewRouteRegistry()
+	for _, m := range s.modules() {
+		if err := s.routes.Register(m); err != nil {
+			return nil, err
+		}
+	}
+	s.routes.Apply(router, s.featureGuard)
+	router.NoRoute(func(c *gin.Context) {
+		processor.RespondError(c, processor.ErrRouteNotFound,
+			fmt.Sprintf("%s %s is not served", c.Request.Method, c.Request.URL.Path))
+	})
 
-	defaultGroup := router.Group("/default")
-	applyRoutes(defaultGroup, s.getDefaultRoute())
+	return router, nil
+}
 
-	spyFamilyGroup := router.Group("/spyfamily")
-	applyRoutes(spyFamilyGroup, s.getSpyFamilyRoute())
+// applyTrustedProxies lets forwarding headers override the peer address only for requests coming
+// from the configured proxies. With no proxies configured the headers are always ignored.
+func applyTrustedProxies(router *gin.Engine, trustedProxies []string) {
+	router.ForwardedByClientIP = true
+	router.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+	if len(trustedProxies) == 0 {
+		trustedProxies = nil
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		logger.SBILog.Errorf("Invalid trusted proxies %v: %+v", trustedProxies, err)
+		if err = router.SetTrustedProxies(nil); err != nil {
+			logger.SBILog.Errorf("Reset trusted proxies failed: %+v", err)
+		}
+	}
+}
 
-	return router
+// RealClientIP returns the address of the client that originated the request. It relies on gin's
+// trusted-proxy handling, set up by applyTrustedProxies: forwarding headers are honored only when the
+// immediate peer is a trusted proxy, and gin skips trusted hops from the right of X-Forwarded-For.
+// Anything recording the client, such as the access log, goes through it so they all agree.
+func RealClientIP(c *gin.Context) string {
+	return c.ClientIP()
 }
 
 func bindRouter(nf app.App, router *gin.Engine, tlsKeyLogPath string) (*http.Server, error) {
@@ -2,9 +2,12 @@ package sbi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Alonza0314/nf-example/internal/logger"
@@ -12,6 +15,11 @@ import (
 	"github.com/Alonza0314/nf-example/pkg/app"
 	"github.com/Alonza0314/nf-example/pkg/factory"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	logger_util "github.com/free5gc/util/logger"
+	"github.com/free5gc/util/version"
 )
 
 type nfApp interface {
@@ -24,14 +32,48 @@ type Server struct {
 
 	httpServer *http.Server
 	router     *gin.Engine
+	routes     *RouteRegistry
+	limiter    *Limiter
+	tap        *Tap
+	accessLog  *AccessLog
+	drainer    *Drainer
+	metrics    *prometheus.Registry
+	duration   *prometheus.HistogramVec
+	log        *logrus.Entry
+
+	createdAt     time.Time
+	tlsKeyLogPath string
+	goroutines    atomic.Int32
+	serving       sync.WaitGroup
+	// inflight counts every request being served, including the control paths the limiter skips.
+	inflight atomic.Int64
 }
 
 func NewServer(nf nfApp, tlsKeyLogPath string) *Server {
 	s := &Server{
-		nfApp: nf,
+		nfApp:         nf,
+		createdAt:     time.Now(),
+		tlsKeyLogPath: tlsKeyLogPath,
+		limiter:       NewLimiter(nf.Config().GetLimiter()),
+		drainer:       NewDrainer(nf.Config().GetDrain()),
+		accessLog:     NewAccessLog(logger.GinLog, nf.Config().GetLogAccess()),
+	}
+	s.duration = newRequestDuration()
+	s.metrics = newMetricsRegistry(s.limiter, s.accessLog, s.duration)
+	if tapConfig := nf.Config().GetTap(); tapConfig.Enable {
+		size := tapConfig.Size
+		if size == 0 {
+			size = factory.NfDefaultTapSize
+		}
+		s.tap = NewTap(size)
 	}
 
-	s.router = newRouter(s)
+	router, err := newRouter(s)
+	if err != nil {
+		logger.SBILog.Errorf("Register routes Error: %+v", err)
+		panic("Server initialization failed")
+	}
+	s.router = router
 
 	server, err := bindRouter(nf, s.router, tlsKeyLogPath)
 	s.httpServer = server
@@ -39,31 +81,83 @@ func NewServer(nf nfApp, tlsKeyLogPath string) *Server {
 		logger.SBILog.Errorf("bind Router Error: %+v", err)
 		panic("Server initialization failed")
 	}
+	s.drainer.TrackConnections(server)
+	s.log = logger.SBILog.WithField(logger_util.FieldListenAddr, server.Addr)
 
 	return s
 }
 
 func (s *Server) Run(wg *sync.WaitGroup) {
-	logger.SBILog.Info("Starting server...")
+	sbiConfig := s.Config().Configuration.Sbi
+	s.log.WithFields(logrus.Fields{
+		"name":     s.Config().Configuration.NfName,
+		"version":  nfVersion(),
+		"scheme":   sbiConfig.Scheme,
+		"features": s.enabledFeatures(),
+	}).Info("Starting server...")
 
 	wg.Add(1)
+	s.serving.Add(1)
+	s.goroutines.Add(1)
 	go func() {
 		defer wg.Done()
-		logger.SBILog.Infof("Start SBI server (listen on %s)", s.httpServer.Addr)
+		defer s.serving.Done()
 
-		err := s.serve()
+		listener, err := net.Listen("tcp", s.httpServer.Addr)
+		if err != nil {
+			s.log.Panicf("HTTP server setup failed: %+v", err)
+		}
+		s.log.WithField("startup", time.Since(s.createdAt)).
+			Infof("SBI server ready (listen on %s)", listener.Addr())
+
+		err = s.serve(listener)
 		if err != http.ErrServerClosed {
-			logger.SBILog.Panicf("HTTP server setup failed: %+v", err)
+			s.log.Panicf("HTTP server setup failed: %+v", err)
 		}
-		logger.SBILog.Infof("SBI server (listen on %s) stopped", s.httpServer.Addr)
+		s.log.Infof("SBI server (listen on %s) stopped", s.httpServer.Addr)
 	}()
 }
 
-func (s *Server) unsecureServe() error {
-	return s.httpServer.ListenAndServe()
+// Handler returns the fully wired HTTP handler, including middlewares, served by the SBI server.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// enabledFeatures lists the optional behaviors switched on by the current configuration.
+func (s *Server) enabledFeatures() []string {
+	features := []string{}
+	if s.Config().Configuration.Sbi.Scheme == "https" {
+		features = append(features, "tls")
+	}
+	if s.tlsKeyLogPath != "" {
+		features = append(features, "tlsKeyLog")
+	}
+	if s.Config().GetTracing().Enable {
+		features = append(features, "tracing")
+	}
+	return features
+}
+
+func (s *Server) maxRequestTimeout() time.Duration {
+	maxTimeout := s.Config().Configuration.Sbi.MaxRequestTimeout
+	if maxTimeout == 0 {
+		maxTimeout = factory.NfDefaultMaxRequestTimeout
+	}
+	return time.Duration(maxTimeout) * time.Millisecond
+}
+
+func nfVersion() string {
+	if version.VERSION == "" {
+		return "unknown"
+	}
+	return version.VERSION
 }
 
-func (s *Server) secureServe() error {
+func (s *Server) unsecureServe(listener net.Listener) error {
+	return s.httpServer.Serve(listener)
+}
+
+func (s *Server) secureServe(listener net.Listener) error {
 	sbiConfig := s.Config().Configuration.Sbi
 
 	pemPath := sbiConfig.Tls.Pem
@@ -76,32 +170,48 @@ func (s *Server) secureServe() error {
 		keyPath = factory.NfDefaultPrivateKeyPath
 	}
 
-	return s.httpServer.ListenAndServeTLS(pemPath, keyPath)
+	return s.httpServer.ServeTLS(listener, pemPath, keyPath)
 }
 
-func (s *Server) serve() error {
+func (s *Server) serve(listener net.Listener) error {
 	sbiConfig := s.Config().Configuration.Sbi
 
 	switch sbiConfig.Scheme {
 	case "http":
-		return s.unsecureServe()
+		return s.unsecureServe(listener)
 	case "https":
-		return s.secureServe()
+		return s.secureServe(listener)
 	default:
 		return fmt.Errorf("invalid SBI scheme: %s", sbiConfig.Scheme)
 	}
 }
 
+// Shutdown stops the server, waiting for in-flight requests up to the shutdown timeout.
+// Requests still running when it expires are logged as abandoned.
 func (s *Server) Shutdown() {
-	s.shutdownHttpServer()
+	inflight := s.inflight.Load()
+	err := s.shutdownHttpServer()
+	s.serving.Wait()
+
+	log := s.log.WithFields(logrus.Fields{
+		"inflight":          inflight,
+		"inflightAbandoned": s.inflight.Load(),
+		"timedOut":          errors.Is(err, context.DeadlineExceeded),
+		"goroutinesStopped": s.goroutines.Swap(0),
+	})
+	if err != nil {
+		log.Warn("SBI server shutdown incomplete")
+		return
+	}
+	log.Info("SBI server shutdown complete")
 }
 
-func (s *Server) shutdownHttpServer() {
-	logger.SBILog.Infoln("Shutdown Http Server...")
+func (s *Server) shutdownHttpServer() error {
+	s.log.Infoln("Shutdown Http Server...")
 	const shutdownTimeout time.Duration = 2 * time.Second
 
 	if s.httpServer == nil {
-		return
+		return nil
 	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
@@ -109,6 +219,7 @@ func (s *Server) shutdownHttpServer() {
 
 	err := s.httpServer.Shutdown(shutdownCtx)
 	if err != nil {
-		logger.SBILog.Errorf("HTTP server shutdown failed: %+v", err)
+		s.log.Errorf("HTTP server shutdown failed: %+v", err)
 	}
+	return err
 }
@@ -120,9 +120,11 @@ func (mr *MocknfAppMockRecorder) SetReportCaller(reportCaller any) *gomock.Call
 }
 
 // Start mocks base method.
-func (m *MocknfApp) Start() {
+func (m *MocknfApp) Start() error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Start")
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Start indicates an expected call of Start.
@@ -0,0 +1,105 @@
+package sbi_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/logger"
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func Test_ServerLifecycleLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	output := &syncBuffer{}
+	originalOutput := logger.Log.Out
+	logger.SetOutput(output)
+	defer logger.SetOutput(originalOutput)
+
+	mockCtrl := gomock.NewController(t)
+	nfApp := sbi.NewMocknfApp(mockCtrl)
+	nfApp.EXPECT().Config().Return(&factory.Config{
+		Configuration: &factory.Configuration{
+			NfName: "ANYA",
+			Sbi: &factory.Sbi{
+				Scheme:      "http",
+				BindingIPv4: "127.0.0.1",
+				Port:        0,
+			},
+		},
+	}).AnyTimes()
+	server := sbi.NewServer(nfApp, "")
+
+	var wg sync.WaitGroup
+	server.Run(&wg)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(output.String(), "SBI server ready") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Server did not become ready, log: %s", output.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	server.Shutdown()
+	wg.Wait()
+
+	lines := strings.Split(output.String(), "\n")
+	findLine := func(msg string) string {
+		for _, line := range lines {
+			if strings.Contains(line, msg) {
+				return line
+			}
+		}
+		t.Errorf("Expected log entry %q, got %s", msg, output.String())
+		return ""
+	}
+
+	t.Run("Start Entry", func(t *testing.T) {
+		line := findLine("Starting server...")
+		for _, field := range []string{"name:ANYA", "version:", "scheme:http", "features:", "LAddr:"} {
+			if !strings.Contains(line, field) {
+				t.Errorf("Expected start entry to contain %q, got %s", field, line)
+			}
+		}
+	})
+
+	t.Run("Ready Entry", func(t *testing.T) {
+		line := findLine("SBI server ready")
+		if !strings.Contains(line, "startup:") {
+			t.Errorf("Expected ready entry to contain startup duration, got %s", line)
+		}
+	})
+
+	t.Run("Stop Entry", func(t *testing.T) {
+		line := findLine("SBI server shutdown complete")
+		for _, field := range []string{"inflight:0", "inflightAbandoned:0", "timedOut:false", "goroutinesStopped:1"} {
+			if !strings.Contains(line, field) {
+				t.Errorf("Expected stop entry to contain %q, got %s", field, line)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package sbi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/perf"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	tapPath        = "/admin/tap"
+	tapMaxBodySize = 2 << 10
+	tapRedacted    = "[REDACTED]"
+)
+
+// tapHeaders are the request headers kept by the tap. Sensitive ones are stored redacted.
+var tapHeaders = []string{"Content-Type", "User-Agent", "Accept", "X-Forwarded-For", "Authorization", "X-Api-Key", "Cookie"}
+
+var tapSensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"X-Api-Key":     true,
+	"Cookie":        true,
+}
+
+type TapEntry struct {
+	Time      time.Time         `json:"time"`
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      string            `json:"body,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+	Status    int               `json:"status"`
+	LatencyMs float64           `json:"latencyMs"`
+}
+
+// Tap is a ring buffer of the last captured requests.
+type Tap struct {
+	mu      sync.Mutex
+	entries []TapEntry
+	next    int
+	full    bool
+}
+
+func NewTap(size int) *Tap {
+	return &Tap{
+		entries: make([]TapEntry, size),
+	}
+}
+
+func (t *Tap) add(entry TapEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.entries) == 0 {
+		return
+	}
+	t.entries[t.next] = entry
+	t.next = (t.next + 1) % len(t.entries)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// Entries returns the captured requests, oldest first, whose path starts with pathPrefix
+// and whose status is in statusClass (1 for 1xx ... 5 for 5xx). Zero values match everything.
+func (t *Tap) Entries(pathPrefix string, statusClass int) []TapEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ordered := t.entries[:t.next]
+	if t.full {
+		ordered = append(append([]TapEntry{}, t.entries[t.next:]...), t.entries[:t.next]...)
+	}
+
+	entries := []TapEntry{}
+	for _, entry := range ordered {
+		if !strings.HasPrefix(entry.Path, pathPrefix) {
+			continue
+		}
+		if statusClass != 0 && entry.Status/100 != statusClass {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (t *Tap) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clear(t.entries)
+	t.next = 0
+	t.full = false
+}
+
+// Middleware captures every request except those to the tap itself.
+func (t *Tap) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.TrimSuffix(c.Request.URL.Path, "/") == tapPath {
+			c.Next()
+			return
+		}
+
+		entry := TapEntry{
+			Time:    time.Now(),
+			Method:  c.Request.Method,
+			Path:    c.Request.URL.Path,
+			Headers: captureHeaders(c.Request.Header),
+		}
+		entry.Body, entry.Truncated = captureBody(c.Request)
+
+		c.Next()
+
+		entry.Status = c.Writer.Status()
+		entry.LatencyMs = perf.Milliseconds(time.Since(entry.Time))
+		t.add(entry)
+	}
+}
+
+func captureHeaders(header http.Header) map[string]string {
+	captured := map[string]string{}
+	for _, name := range tapHeaders {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		if tapSensitiveHeaders[name] {
+			value = tapRedacted
+		}
+		captured[name] = value
+	}
+	return captured
+}
+
+// captureBody reads at most tapMaxBodySize bytes of the body and puts them back in front of the rest,
+// so handlers still see the complete body.
+func captureBody(req *http.Request) (string, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", false
+	}
+
+	head, err := io.ReadAll(io.LimitReader(req.Body, tapMaxBodySize+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), req.Body), req.Body}
+	if err != nil {
+		return "", false
+	}
+
+	if len(head) > tapMaxBodySize {
+		return string(head[:tapMaxBodySize]), true
+	}
+	return string(head), false
+}
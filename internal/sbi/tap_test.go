@@ -0,0 +1,138 @@
+package sbi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+func getTap(t *testing.T, server *testutil.TestServer, query string) []sbi.TapEntry {
+	t.Helper()
+
+	status, body := server.Do(t, http.MethodGet, "/admin/tap"+query, nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, status, body)
+	}
+
+	var entries []sbi.TapEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	return entries
+}
+
+func Test_Tap(t *testing.T) {
+	const TAP_SIZE = 3
+
+	cfg := testutil.DefaultConfig()
+	cfg.Configuration.Tap = &factory.Tap{
+		Enable: true,
+		Size:   TAP_SIZE,
+	}
+	server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+
+	t.Run("Capture And Redact", func(t *testing.T) {
+		server.DoWithHeaders(t, http.MethodGet, "/spyfamily/character/Anya", nil, http.Header{
+			"Authorization": {"Bearer secret"},
+			"X-Api-Key":     {"secret"},
+			"Cookie":        {"session=secret"},
+			"User-Agent":    {"tap-test"},
+		})
+
+		entries := getTap(t, server, "")
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 captured request, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Method != http.MethodGet || entry.Path != "/spyfamily/character/Anya" || entry.Status != http.StatusOK {
+			t.Errorf("Unexpected captured request: %+v", entry)
+		}
+		if entry.Headers["User-Agent"] != "tap-test" {
+			t.Errorf("Expected User-Agent tap-test, got %s", entry.Headers["User-Agent"])
+		}
+		for _, header := range []string{"Authorization", "X-Api-Key", "Cookie"} {
+			if entry.Headers[header] != "[REDACTED]" {
+				t.Errorf("Expected %s to be redacted, got %s", header, entry.Headers[header])
+			}
+		}
+	})
+
+	t.Run("Truncate Body", func(t *testing.T) {
+		body := `{"message": "` + strings.Repeat("x", 3<<10) + `"}`
+		status, _ := server.Do(t, http.MethodPost, "/admin/maintenance", strings.NewReader(body))
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, status)
+		}
+
+		entries := getTap(t, server, "?path=/admin/maintenance")
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 captured request, got %d", len(entries))
+		}
+		if !entries[0].Truncated || len(entries[0].Body) != 2<<10 {
+			t.Errorf("Expected body truncated to 2 KiB, got %d bytes (truncated %v)",
+				len(entries[0].Body), entries[0].Truncated)
+		}
+	})
+
+	t.Run("Filter By Status Class", func(t *testing.T) {
+		entries := getTap(t, server, "?status=4xx")
+		if len(entries) != 1 || entries[0].Status != http.StatusBadRequest {
+			t.Errorf("Expected only the 400 request, got %+v", entries)
+		}
+
+		status, _ := server.Do(t, http.MethodGet, "/admin/tap?status=4", nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, status)
+		}
+	})
+
+	t.Run("Keep Last Requests", func(t *testing.T) {
+		for i := 0; i < TAP_SIZE; i++ {
+			server.Do(t, http.MethodGet, "/default/", nil)
+		}
+
+		entries := getTap(t, server, "")
+		if len(entries) != TAP_SIZE {
+			t.Fatalf("Expected %d captured requests, got %d", TAP_SIZE, len(entries))
+		}
+		for _, entry := range entries {
+			if entry.Path != "/default/" {
+				t.Errorf("Expected older requests to be evicted, got %s", entry.Path)
+			}
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		status, _ := server.Do(t, http.MethodDelete, "/admin/tap", nil)
+		if status != http.StatusNoContent {
+			t.Errorf("Expected status code %d, got %d", http.StatusNoContent, status)
+		}
+		if entries := getTap(t, server, ""); len(entries) != 0 {
+			t.Errorf("Expected no captured requests, got %d", len(entries))
+		}
+	})
+}
+
+func Test_TapDisabledByDefault(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	server.Do(t, http.MethodGet, "/default/", nil)
+	status, body := server.Do(t, http.MethodGet, "/admin/tap", nil)
+	if status != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, status)
+	}
+
+	var problem processor.ProblemDetails
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("Failed to unmarshal body: %s", err)
+	}
+	if problem.Code != processor.ErrTapDisabled {
+		t.Errorf("Expected code %s, got %s", processor.ErrTapDisabled, problem.Code)
+	}
+}
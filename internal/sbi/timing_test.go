@@ -0,0 +1,65 @@
+package sbi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/Alonza0314/nf-example/internal/testutil"
+)
+
+func Test_ServerTiming(t *testing.T) {
+	const TARGET_PATH = "/spyfamily/character/Anya"
+
+	serve := func(ts *testutil.TestServer, debug bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, TARGET_PATH, nil)
+		if debug {
+			req.Header.Set("X-Debug-Timing", "1")
+		}
+		w := httptest.NewRecorder()
+		ts.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	entry := regexp.MustCompile(`(\w+);dur=([0-9.]+)`)
+	assertPhases := func(t *testing.T, header string) {
+		phases := map[string]bool{}
+		for _, match := range entry.FindAllStringSubmatch(header, -1) {
+			dur, err := strconv.ParseFloat(match[2], 64)
+			if err != nil || dur < 0 {
+				t.Errorf("Expected non-negative duration for %s, got %s", match[1], match[2])
+			}
+			phases[match[1]] = true
+		}
+		for _, phase := range []string{"binding", "processor", "storage", "serialization"} {
+			if !phases[phase] {
+				t.Errorf("Expected phase %s in Server-Timing, got %q", phase, header)
+			}
+		}
+	}
+
+	t.Run("Debug Header", func(t *testing.T) {
+		ts := testutil.NewTestServer(t, testutil.Options{})
+		w := serve(ts, true)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+		assertPhases(t, w.Header().Get("Server-Timing"))
+	})
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		ts := testutil.NewTestServer(t, testutil.Options{})
+		if header := serve(ts, false).Header().Get("Server-Timing"); header != "" {
+			t.Errorf("Expected no Server-Timing header, got %q", header)
+		}
+	})
+
+	t.Run("Enabled By Config", func(t *testing.T) {
+		cfg := testutil.DefaultConfig()
+		cfg.Configuration.Sbi.ServerTiming = true
+		ts := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+		assertPhases(t, serve(ts, false).Header().Get("Server-Timing"))
+	})
+}
@@ -0,0 +1,23 @@
+package testutil
+
+import (
+	"fmt"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+)
+
+// SeedCharacters adds n deterministic SPYxFAMILY characters to the context and returns their first names.
+// Character i is named "Agent<i>" with the last name "Family<i>", both zero padded.
+func SeedCharacters(nfCtx *nf_context.NFContext, n int) []string {
+	if nfCtx.SpyFamilyData == nil {
+		nfCtx.SpyFamilyData = make(map[string]string, n)
+	}
+
+	names := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("Agent%03d", i)
+		nfCtx.SpyFamilyData[name] = fmt.Sprintf("Family%03d", i)
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// AssertJSONGolden compares a JSON body against the golden file, ignoring formatting differences.
+// Run the tests with -update to rewrite the golden file from the current body.
+func AssertJSONGolden(t *testing.T, body []byte, goldenFile string) {
+	t.Helper()
+
+	actual, err := indentJSON(body)
+	if err != nil {
+		t.Fatalf("Response is not valid JSON: %s\n%s", err, body)
+	}
+
+	if *update {
+		if err = os.MkdirAll(filepath.Dir(goldenFile), 0o750); err != nil {
+			t.Fatalf("Failed to create golden directory: %s", err)
+		}
+		if err = os.WriteFile(goldenFile, actual, 0o600); err != nil {
+			t.Fatalf("Failed to update golden file: %s", err)
+		}
+		return
+	}
+
+	content, err := os.ReadFile(filepath.Clean(goldenFile))
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with -update to create it): %s", err)
+	}
+	expected, err := indentJSON(content)
+	if err != nil {
+		t.Fatalf("Golden file %s is not valid JSON: %s", goldenFile, err)
+	}
+
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("Response does not match golden file %s\nExpected:\n%s\nGot:\n%s", goldenFile, expected, actual)
+	}
+}
+
+func indentJSON(data []byte) ([]byte, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	indented, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(indented, '\n'), nil
+}
@@ -0,0 +1,167 @@
+package testutil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+// Options customizes the server built by NewTestServer. Zero values fall back to defaults.
+type Options struct {
+	// Config is returned by the mocked app. Defaults to DefaultConfig().
+	Config *factory.Config
+	// Context is returned by the mocked app. Defaults to a real context built by NewNfContext.
+	Context *nf_context.NFContext
+}
+
+// TestServer bundles a fully wired SBI server with the mocks and state behind it.
+type TestServer struct {
+	*sbi.Server
+
+	App       *sbi.MocknfApp
+	Config    *factory.Config
+	Context   *nf_context.NFContext
+	Processor *processor.Processor
+	HTTP      *httptest.Server
+}
+
+// DefaultConfig returns a minimal valid configuration listening on a loopback address.
+func DefaultConfig() *factory.Config {
+	return &factory.Config{
+		Info: &factory.Info{
+			Version: "1.0.0",
+		},
+		Configuration: &factory.Configuration{
+			NfName: "ANYA",
+			Sbi: &factory.Sbi{
+				Scheme:      "http",
+				BindingIPv4: "127.0.0.1",
+				Port:        0,
+			},
+		},
+		Logger: &factory.Logger{
+			Level: "info",
+		},
+	}
+}
+
+// NewTestServer wires a mocked app, a real processor and the complete router, and serves it with httptest.
+// Everything is torn down automatically when the test ends. The global factory.NfConfig and the context
+// returned by nf_context.GetSelf are left untouched, so parallel tests do not share state.
+func NewTestServer(t *testing.T, opts Options) *TestServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	nfCtx := opts.Context
+	if nfCtx == nil {
+		nfCtx = nf_context.NewNfContext(cfg)
+	}
+
+	mockCtrl := gomock.NewController(t)
+	nfApp := sbi.NewMocknfApp(mockCtrl)
+	nfApp.EXPECT().Config().Return(cfg).AnyTimes()
+	nfApp.EXPECT().Context().Return(nfCtx).AnyTimes()
+
+	p, err := processor.NewProcessor(nfApp)
+	if err != nil {
+		t.Fatalf("Failed to create processor: %s", err)
+	}
+	nfApp.EXPECT().Processor().Return(p).AnyTimes()
+
+	ts := &TestServer{
+		Server:    sbi.NewServer(nfApp, ""),
+		App:       nfApp,
+		Config:    cfg,
+		Context:   nfCtx,
+		Processor: p,
+	}
+	ts.HTTP = httptest.NewServer(ts.Handler())
+	t.Cleanup(ts.HTTP.Close)
+
+	return ts
+}
+
+// Do sends a request through the served router and returns the status code and body.
+func (ts *TestServer) Do(t *testing.T, method, path string, body io.Reader) (int, []byte) {
+	t.Helper()
+
+	resp, respBody := ts.DoResponse(t, method, path, body)
+	return resp.StatusCode, respBody
+}
+
+// DoResponse is like Do but returns the whole response, whose body has already been read and closed.
+func (ts *TestServer) DoResponse(t *testing.T, method, path string, body io.Reader) (*http.Response, []byte) {
+	t.Helper()
+
+	return ts.DoWithHeaders(t, method, path, body, nil)
+}
+
+// DoWithHeaders is like DoResponse but sends the given headers too.
+func (ts *TestServer) DoWithHeaders(t *testing.T, method, path string, body io.Reader,
+	header http.Header,
+) (*http.Response, []byte) {
+	t.Helper()
+
+	req := NewRequest(t, method, ts.HTTP.URL+path, body, header)
+	resp, err := ts.HTTP.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %s", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			t.Errorf("Failed to close response body: %s", closeErr)
+		}
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response body: %s", err)
+	}
+	return resp, respBody
+}
+
+// NewRequest creates a request with the given headers, whose names need not be canonical.
+// A request with a body is sent as JSON unless header sets another Content-Type.
+func NewRequest(t *testing.T, method, target string, body io.Reader, header http.Header) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, target, body)
+	if err != nil {
+		t.Fatalf("Failed to create request: %s", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return req
+}
+
+// NewGinContext creates a gin context for calling handlers directly, bypassing routing.
+func NewGinContext(t *testing.T, method, path string, body io.Reader) (*httptest.ResponseRecorder, *gin.Context) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	httpRecorder := httptest.NewRecorder()
+	ginCtx, _ := gin.CreateTestContext(httpRecorder)
+	ginCtx.Request = NewRequest(t, method, path, body, nil)
+	return httpRecorder, ginCtx
+}
@@ -0,0 +1,105 @@
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phase names a part of request handling reported in the Server-Timing header.
+type Phase string
+
+const (
+	PhaseBinding       Phase = "binding"
+	PhaseProcessor     Phase = "processor"
+	PhaseStorage       Phase = "storage"
+	PhaseSerialization Phase = "serialization"
+)
+
+type recorderKey struct{}
+
+type phaseTiming struct {
+	phase    Phase
+	start    time.Time
+	duration time.Duration
+	open     bool
+}
+
+// Recorder collects the phases of one request. A phase timed more than once is reported with
+// the sum of its durations.
+type Recorder struct {
+	mu     sync.Mutex
+	phases []phaseTiming
+}
+
+// WithRecorder returns a context carrying a new recorder, so phases started with it are measured.
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{}
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+func noop() {}
+
+// Start starts timing a phase and returns the function ending it. Without a recorder in ctx it
+// returns a shared no-op, so disabled timing costs one context lookup and no allocation.
+func Start(ctx context.Context, phase Phase) func() {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return noop
+	}
+
+	r.mu.Lock()
+	index := len(r.phases)
+	r.phases = append(r.phases, phaseTiming{phase: phase, start: time.Now(), open: true})
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if p := &r.phases[index]; p.open {
+			p.duration = time.Since(p.start)
+			p.open = false
+		}
+	}
+}
+
+// Durations returns the time spent in each phase, counting phases still open until now.
+func (r *Recorder) Durations() map[Phase]time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	durations := make(map[Phase]time.Duration, len(r.phases))
+	for _, p := range r.phases {
+		if p.open {
+			durations[p.phase] += time.Since(p.start)
+			continue
+		}
+		durations[p.phase] += p.duration
+	}
+	return durations
+}
+
+// Header formats the phases as a Server-Timing header value, in milliseconds and in the order
+// they were first started.
+func (r *Recorder) Header() string {
+	durations := r.Durations()
+
+	r.mu.Lock()
+	order := make([]Phase, 0, len(durations))
+	seen := make(map[Phase]bool, len(durations))
+	for _, p := range r.phases {
+		if !seen[p.phase] {
+			seen[p.phase] = true
+			order = append(order, p.phase)
+		}
+	}
+	r.mu.Unlock()
+
+	entries := make([]string, 0, len(order))
+	for _, phase := range order {
+		entries = append(entries, fmt.Sprintf("%s;dur=%.3f", phase, float64(durations[phase].Microseconds())/1000))
+	}
+	return strings.Join(entries, ", ")
+}
@@ -0,0 +1,46 @@
+package timing_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/timing"
+)
+
+func Test_StartWithoutRecorder(t *testing.T) {
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(100, func() {
+		timing.Start(ctx, timing.PhaseProcessor)()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocation without a recorder, got %v", allocs)
+	}
+}
+
+func Test_RecorderHeader(t *testing.T) {
+	ctx, recorder := timing.WithRecorder(context.Background())
+
+	endProcessor := timing.Start(ctx, timing.PhaseProcessor)
+	timing.Start(ctx, timing.PhaseBinding)()
+	endStorage := timing.Start(ctx, timing.PhaseStorage)
+	time.Sleep(2 * time.Millisecond)
+	endStorage()
+	timing.Start(ctx, timing.PhaseStorage)()
+	endProcessor()
+
+	durations := recorder.Durations()
+	if durations[timing.PhaseStorage] < 2*time.Millisecond {
+		t.Errorf("Expected storage to take at least 2ms, got %s", durations[timing.PhaseStorage])
+	}
+	if durations[timing.PhaseProcessor] < durations[timing.PhaseStorage] {
+		t.Errorf("Expected processor %s to cover storage %s", durations[timing.PhaseProcessor], durations[timing.PhaseStorage])
+	}
+
+	header := recorder.Header()
+	pattern := regexp.MustCompile(`^processor;dur=\d+\.\d{3}, binding;dur=\d+\.\d{3}, storage;dur=\d+\.\d{3}$`)
+	if !pattern.MatchString(header) {
+		t.Errorf("Expected phases in start order, got %q", header)
+	}
+}
@@ -0,0 +1,221 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/internal/tracing"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	if _, err := tracing.Init(context.Background(), &factory.Tracing{}, "ANYA", "test", nil); err != nil {
+		t.Fatalf("Failed to init tracing: %s", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(original)
+	})
+	return recorder
+}
+
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("Span %q not recorded", name)
+	return nil
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, expected attribute.KeyValue) bool {
+	for _, attr := range span.Attributes() {
+		if attr == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_Middleware(t *testing.T) {
+	recorder := setupRecorder(t)
+	server := testutil.NewTestServer(t, testutil.Options{})
+
+	const TRACE_ID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	server.DoWithHeaders(t, http.MethodGet, "/spyfamily/character/Anya", nil, http.Header{
+		"traceparent": {"00-" + TRACE_ID + "-00f067aa0ba902b7-01"},
+	})
+
+	spans := recorder.Ended()
+	serverSpan := findSpan(t, spans, "GET /spyfamily/character/:Name")
+	processorSpan := findSpan(t, spans, "FindSpyFamilyCharacterName")
+
+	t.Run("Server Span Continues Remote Trace", func(t *testing.T) {
+		if serverSpan.SpanKind() != trace.SpanKindServer {
+			t.Errorf("Expected server span kind, got %s", serverSpan.SpanKind())
+		}
+		if serverSpan.SpanContext().TraceID().String() != TRACE_ID {
+			t.Errorf("Expected trace ID %s, got %s", TRACE_ID, serverSpan.SpanContext().TraceID())
+		}
+		if !serverSpan.Parent().IsRemote() {
+			t.Errorf("Expected server span parent to be remote")
+		}
+		for _, expected := range []attribute.KeyValue{
+			tracing.AttrHTTPRoute.String("/spyfamily/character/:Name"),
+			tracing.AttrHTTPStatusCode.Int(http.StatusOK),
+		} {
+			if !hasAttribute(serverSpan, expected) {
+				t.Errorf("Expected server span attribute %v, got %v", expected, serverSpan.Attributes())
+			}
+		}
+	})
+
+	t.Run("Processor Span Is Child Of Server Span", func(t *testing.T) {
+		if processorSpan.Parent().SpanID() != serverSpan.SpanContext().SpanID() {
+			t.Errorf("Expected processor span parent %s, got %s",
+				serverSpan.SpanContext().SpanID(), processorSpan.Parent().SpanID())
+		}
+		expected := attribute.String("spyfamily.character.name", "Anya")
+		if !hasAttribute(processorSpan, expected) {
+			t.Errorf("Expected processor span attribute %v, got %v", expected, processorSpan.Attributes())
+		}
+	})
+}
+
+func Test_Transport(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	var receivedTraceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.URL+"/notify", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %s", err)
+	}
+	client := &http.Client{Transport: tracing.Transport(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %s", err)
+	}
+	if err = resp.Body.Close(); err != nil {
+		t.Fatalf("Failed to close response body: %s", err)
+	}
+	parent.End()
+
+	clientSpan := findSpan(t, recorder.Ended(), "POST "+req.URL.Host)
+	if clientSpan.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected client span kind, got %s", clientSpan.SpanKind())
+	}
+	if clientSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected client span to be a child of the caller span")
+	}
+
+	expected := "00-" + clientSpan.SpanContext().TraceID().String() + "-" +
+		clientSpan.SpanContext().SpanID().String() + "-01"
+	if receivedTraceparent != expected {
+		t.Errorf("Expected traceparent %s, got %s", expected, receivedTraceparent)
+	}
+}
+
+func Test_InitSamplingRatio(t *testing.T) {
+	zero := 0.0
+	testCases := []struct {
+		name     string
+		ratio    *float64
+		expected bool
+	}{
+		{"Omitted Samples Everything", nil, true},
+		{"Zero Samples Nothing", &zero, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := otel.GetTracerProvider()
+			shutdown, err := tracing.Init(context.Background(), &factory.Tracing{
+				Enable:        true,
+				Endpoint:      "127.0.0.1:4318",
+				Insecure:      true,
+				SamplingRatio: tc.ratio,
+			}, "ANYA", "test", nil)
+			if err != nil {
+				t.Fatalf("Failed to init tracing: %s", err)
+			}
+			t.Cleanup(func() {
+				otel.SetTracerProvider(original)
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				defer cancel()
+				_ = shutdown(ctx)
+			})
+
+			_, span := tracing.Start(context.Background(), "root")
+			defer span.End()
+			if sampled := span.SpanContext().IsSampled(); sampled != tc.expected {
+				t.Errorf("Expected sampled %v, got %v", tc.expected, sampled)
+			}
+		})
+	}
+}
+
+type recordingTransport struct {
+	paths chan string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.paths <- req.URL.Path
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/x-protobuf"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func Test_InitExporterClient(t *testing.T) {
+	rt := &recordingTransport{paths: make(chan string, 1)}
+	original := otel.GetTracerProvider()
+	shutdown, err := tracing.Init(context.Background(), &factory.Tracing{
+		Enable:   true,
+		Endpoint: "collector.example:4318",
+		Insecure: true,
+	}, "ANYA", "test", &http.Client{Transport: rt})
+	if err != nil {
+		t.Fatalf("Failed to init tracing: %s", err)
+	}
+	defer otel.SetTracerProvider(original)
+
+	_, span := tracing.Start(context.Background(), "root")
+	span.End()
+	if err = shutdown(context.Background()); err != nil {
+		t.Fatalf("Failed to shut down tracing: %s", err)
+	}
+
+	select {
+	case path := <-rt.paths:
+		if path != "/v1/traces" {
+			t.Errorf("Expected export to /v1/traces, got %s", path)
+		}
+	default:
+		t.Errorf("Expected spans to be exported through the given client")
+	}
+}
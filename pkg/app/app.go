@@ -10,7 +10,7 @@ type App interface {
 	SetLogLevel(level string)
 	SetReportCaller(reportCaller bool)
 
-	Start()
+	Start() error
 	Terminate()
 
 	Context() *nf_context.NFContext
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CapabilitiesVersion is the version of the GET /capabilities document this client understands.
+	CapabilitiesVersion = 1
+	// DefaultCacheTTL is how long a fetched capabilities document is used before it is fetched again.
+	DefaultCacheTTL = 30 * time.Second
+	// DefaultParallelism caps concurrent lookups when the NF advertises no inflight limit.
+	DefaultParallelism = 4
+
+	featureSearch = "search"
+)
+
+// ErrNotSupported is returned, without sending a request, for a call the NF does not currently serve.
+var ErrNotSupported = errors.New("not supported by the NF")
+
+// Capabilities is the part of the GET /capabilities document the client adapts to.
+type Capabilities struct {
+	Version  int             `json:"version"`
+	Features map[string]bool `json:"features"`
+	Limits   struct {
+		MaxInflight int `json:"maxInflight"`
+	} `json:"limits"`
+}
+
+// Error is a problem details answer of the NF.
+type Error struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+// Client calls the SBI of the NF. It fetches GET /capabilities on first use, caches it for the cache
+// TTL, and adjusts to it: calls to disabled features fail fast with ErrNotSupported, and batch lookups
+// stay within the advertised inflight limit instead of queueing on the server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cacheTTL   time.Duration
+
+	mu           sync.Mutex
+	capabilities *Capabilities
+	fetchedAt    time.Time
+}
+
+// New creates a client for the NF at baseURL, such as "http://127.0.0.163:8000". A nil httpClient
+// uses http.DefaultClient and a zero cacheTTL uses DefaultCacheTTL.
+func New(baseURL string, httpClient *http.Client, cacheTTL time.Duration) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+		cacheTTL:   cacheTTL,
+	}
+}
+
+// Capabilities returns the cached capabilities document, fetching it when missing or expired. A document
+// of another version is replaced by an empty one, so the client falls back to its defaults.
+func (cl *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.capabilities != nil && time.Since(cl.fetchedAt) < cl.cacheTTL {
+		return cl.capabilities, nil
+	}
+
+	capabilities := &Capabilities{}
+	if err := cl.getJSON(ctx, "/capabilities", capabilities); err != nil {
+		return nil, fmt.Errorf("fetch capabilities failed: %w", err)
+	}
+	if capabilities.Version != CapabilitiesVersion {
+		capabilities = &Capabilities{Version: capabilities.Version}
+	}
+	cl.capabilities = capabilities
+	cl.fetchedAt = time.Now()
+	return capabilities, nil
+}
+
+// Refresh drops the cached capabilities document, so the next call fetches it again.
+func (cl *Client) Refresh() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.capabilities = nil
+}
+
+// Parallelism returns how many lookups a batch sends at once: the inflight limit of the NF, if any.
+func (cl *Client) Parallelism(ctx context.Context) (int, error) {
+	capabilities, err := cl.Capabilities(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if limit := capabilities.Limits.MaxInflight; limit > 0 {
+		return limit, nil
+	}
+	return DefaultParallelism, nil
+}
+
+// Character returns the answer of GET /spyfamily/character/{firstName}, such as "Character: Anya Forger".
+func (cl *Client) Character(ctx context.Context, firstName string) (string, error) {
+	body, err := cl.get(ctx, "/spyfamily/character/"+url.PathEscape(firstName))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Characters looks up several characters at once, keyed by first name, never sending more requests
+// at a time than Parallelism allows.
+func (cl *Client) Characters(ctx context.Context, firstNames []string) (map[string]string, error) {
+	parallelism, err := cl.Parallelism(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	characters := make(map[string]string, len(firstNames))
+	slots := make(chan struct{}, parallelism)
+	for _, firstName := range firstNames {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			character, lookupErr := cl.Character(ctx, firstName)
+			mu.Lock()
+			defer mu.Unlock()
+			if lookupErr != nil {
+				errs = append(errs, fmt.Errorf("[%s]: %w", firstName, lookupErr))
+				return
+			}
+			characters[firstName] = character
+		}()
+	}
+	wg.Wait()
+	return characters, errors.Join(errs...)
+}
+
+// SearchByLastName lists the first names of the characters with a last name. It needs the search
+// feature and returns ErrNotSupported while the NF has it disabled.
+func (cl *Client) SearchByLastName(ctx context.Context, lastName string) ([]string, error) {
+	capabilities, err := cl.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !capabilities.Features[featureSearch] {
+		return nil, fmt.Errorf("search: %w", ErrNotSupported)
+	}
+
+	var firstNames []string
+	if err = cl.getJSON(ctx, "/spyfamily/search?lastName="+url.QueryEscape(lastName), &firstNames); err != nil {
+		return nil, err
+	}
+	return firstNames, nil
+}
+
+func (cl *Client) getJSON(ctx context.Context, path string, out any) error {
+	body, err := cl.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// get sends a GET request and returns the body of a successful answer. A problem details answer
+// is returned as an *Error.
+func (cl *Client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cl.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := cl.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		problem := &Error{Status: resp.StatusCode}
+		if err = json.Unmarshal(body, problem); err != nil {
+			problem.Detail = string(body)
+		}
+		return nil, problem
+	}
+	return body, nil
+}
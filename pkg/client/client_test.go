@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/client"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+)
+
+// countingTransport records how many requests are in flight at once, holding each one briefly so
+// concurrent requests overlap.
+type countingTransport struct {
+	mu          sync.Mutex
+	inflight    int
+	maxInflight int
+	paths       []string
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.mu.Lock()
+	ct.inflight++
+	ct.maxInflight = max(ct.maxInflight, ct.inflight)
+	ct.paths = append(ct.paths, req.URL.Path)
+	ct.mu.Unlock()
+
+	defer func() {
+		ct.mu.Lock()
+		ct.inflight--
+		ct.mu.Unlock()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (ct *countingTransport) requests(path string) int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	n := 0
+	for _, p := range ct.paths {
+		if p == path {
+			n++
+		}
+	}
+	return n
+}
+
+func Test_ClientFeatureFlag(t *testing.T) {
+	server := testutil.NewTestServer(t, testutil.Options{})
+	transport := &countingTransport{}
+	cl := client.New(server.HTTP.URL, &http.Client{Transport: transport}, time.Hour)
+	ctx := context.Background()
+
+	if _, err := cl.SearchByLastName(ctx, "Forger"); !errors.Is(err, client.ErrNotSupported) {
+		t.Errorf("Expected %v while search is disabled, got %v", client.ErrNotSupported, err)
+	}
+	if n := transport.requests("/spyfamily/search"); n != 0 {
+		t.Errorf("Expected no search request while search is disabled, got %d", n)
+	}
+
+	status, body := server.Do(t, http.MethodPut, "/admin/features/search", strings.NewReader(`{"enabled": true}`))
+	if status != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, status, body)
+	}
+
+	if _, err := cl.SearchByLastName(ctx, "Forger"); !errors.Is(err, client.ErrNotSupported) {
+		t.Errorf("Expected the cached capabilities to be used until refreshed, got %v", err)
+	}
+	if n := transport.requests("/capabilities"); n != 1 {
+		t.Errorf("Expected capabilities to be fetched once, got %d", n)
+	}
+
+	cl.Refresh()
+	firstNames, err := cl.SearchByLastName(ctx, "Forger")
+	if err != nil {
+		t.Fatalf("Failed to search after enabling search: %s", err)
+	}
+	if expected := []string{"Anya", "Bond", "Loid", "Yor"}; !slices.Equal(firstNames, expected) {
+		t.Errorf("Expected %v, got %v", expected, firstNames)
+	}
+}
+
+func Test_ClientInflightLimit(t *testing.T) {
+	const MAX_INFLIGHT = 1
+
+	firstNames := []string{"Anya", "Loid", "Yor", "Bond"}
+
+	testCases := []struct {
+		name                string
+		limiter             *factory.Limiter
+		expectedParallelism int
+	}{
+		{"No Limit", nil, client.DefaultParallelism},
+		{"Limited", &factory.Limiter{MaxInflight: MAX_INFLIGHT}, MAX_INFLIGHT},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testutil.DefaultConfig()
+			cfg.Configuration.Limiter = tc.limiter
+			server := testutil.NewTestServer(t, testutil.Options{Config: cfg})
+			transport := &countingTransport{}
+			cl := client.New(server.HTTP.URL, &http.Client{Transport: transport}, 0)
+
+			parallelism, err := cl.Parallelism(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to read parallelism: %s", err)
+			}
+			if parallelism != tc.expectedParallelism {
+				t.Errorf("Expected parallelism %d, got %d", tc.expectedParallelism, parallelism)
+			}
+
+			characters, err := cl.Characters(context.Background(), firstNames)
+			if err != nil {
+				t.Fatalf("Failed to look up characters: %s", err)
+			}
+			if characters["Anya"] != "Character: Anya Forger" || len(characters) != len(firstNames) {
+				t.Errorf("Unexpected characters: %v", characters)
+			}
+			if transport.maxInflight > tc.expectedParallelism {
+				t.Errorf("Expected at most %d requests at once, got %d", tc.expectedParallelism, transport.maxInflight)
+			}
+			if tc.limiter == nil && transport.maxInflight < 2 {
+				t.Errorf("Expected lookups to run concurrently without a limit, got %d at once", transport.maxInflight)
+			}
+		})
+	}
+}
@@ -2,10 +2,14 @@ package factory
 
 import (
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/Alonza0314/nf-example/internal/logger"
 	"github.com/asaskevich/govalidator"
+	"github.com/sirupsen/logrus"
 
 	"github.com/free5gc/openapi/models"
 )
@@ -15,6 +19,12 @@ const (
 	NfDefaultTLSKeyLogPath  = "./log/nfsslkey.log"
 	NfDefaultCertPemPath    = "./cert/nf.pem"
 	NfDefaultPrivateKeyPath = "./cert/nf.key"
+
+	NfDefaultMaintenanceRetryAfter = 120
+	NfDefaultTapSize               = 100
+	NfDefaultMaxRequestTimeout     = 30000
+	NfDefaultDrainGracePeriod      = 5000
+	NfDefaultDrainRetryAfter       = 5
 )
 
 type Config struct {
@@ -30,21 +40,131 @@ type Info struct {
 }
 
 type Configuration struct {
-	NfName string `yaml:"nfName,omitempty"`
-	Sbi    *Sbi   `yaml:"sbi"`
+	NfName      string       `yaml:"nfName,omitempty"`
+	StateFile   string       `yaml:"stateFile,omitempty" valid:"optional"`
+	Sbi         *Sbi         `yaml:"sbi"`
+	Tracing     *Tracing     `yaml:"tracing,omitempty" valid:"optional"`
+	Maintenance *Maintenance `yaml:"maintenance,omitempty" valid:"optional"`
+	Seed        *Seed        `yaml:"seed,omitempty" valid:"optional"`
+	Limiter     *Limiter     `yaml:"limiter,omitempty" valid:"optional"`
+	Tap         *Tap         `yaml:"tap,omitempty" valid:"optional"`
+	Drain       *Drain       `yaml:"drain,omitempty" valid:"optional"`
+	Client      *Client      `yaml:"client,omitempty" valid:"optional"`
+	// Dependencies are probed over TCP at startup.
+	Dependencies []Dependency `yaml:"dependencies,omitempty" valid:"optional"`
+	// Features switches routes flagged with a feature on or off. Flagged routes missing here are disabled.
+	Features map[string]bool `yaml:"features,omitempty" valid:"optional"`
+	// FeatureDisabledStatus is the status of a disabled route: 404 (default) hides it, 501 explains it.
+	FeatureDisabledStatus int `yaml:"featureDisabledStatus,omitempty" valid:"optional"`
 }
 
 type Logger struct {
-	Enable       bool   `yaml:"enable" valid:"type(bool)"`
-	Level        string `yaml:"level" valid:"required,in(trace|debug|info|warn|error|fatal|panic)"`
-	ReportCaller bool   `yaml:"reportCaller" valid:"type(bool)"`
+	Enable       bool              `yaml:"enable" valid:"type(bool)"`
+	Level        string            `yaml:"level" valid:"required,in(trace|debug|info|warn|error|fatal|panic)"`
+	ReportCaller bool              `yaml:"reportCaller" valid:"type(bool)"`
+	Modules      map[string]string `yaml:"modules,omitempty" valid:"optional"`
+	File         *LogFile          `yaml:"file,omitempty" valid:"optional"`
+	Access       *AccessLog        `yaml:"access,omitempty" valid:"optional"`
+}
+
+type LogFile struct {
+	Path       string `yaml:"path" valid:"type(string),minstringlength(1),required"`
+	MaxSize    int    `yaml:"maxSize,omitempty" valid:"optional"`
+	MaxBackups int    `yaml:"maxBackups,omitempty" valid:"optional"`
+}
+
+// AccessLog samples the log entry written for every request. Errors and slow requests are always logged.
+type AccessLog struct {
+	// SampleRate logs 1 in SampleRate successful requests. Zero or one logs all of them.
+	SampleRate int `yaml:"sampleRate,omitempty" valid:"optional"`
+	// SlowThreshold in milliseconds marks slower requests with slow=true. Zero disables it.
+	SlowThreshold int `yaml:"slowThreshold,omitempty" valid:"optional"`
 }
 
 type Sbi struct {
-	Scheme      models.UriScheme `yaml:"scheme"`
-	BindingIPv4 string           `yaml:"bindingIPv4,omitempty" valid:"host,required"`
-	Port        int              `yaml:"port"`
-	Tls         *Tls             `yaml:"tls,omitempty" valid:"optional"`
+	Scheme         models.UriScheme `yaml:"scheme"`
+	BindingIPv4    string           `yaml:"bindingIPv4,omitempty" valid:"host,required"`
+	Port           int              `yaml:"port"`
+	Tls            *Tls             `yaml:"tls,omitempty" valid:"optional"`
+	TrustedProxies []string         `yaml:"trustedProxies,omitempty" valid:"optional"`
+	// MaxRequestTimeout caps the X-Request-Timeout header, in milliseconds.
+	MaxRequestTimeout int `yaml:"maxRequestTimeout,omitempty" valid:"optional"`
+	// StrictDecoding rejects request bodies with unknown JSON fields. Defaults to true.
+	StrictDecoding *bool `yaml:"strictDecoding,omitempty" valid:"optional"`
+	// ServerTiming reports request phases in the Server-Timing header of every response,
+	// not only of requests sending X-Debug-Timing: 1.
+	ServerTiming bool `yaml:"serverTiming,omitempty" valid:"optional"`
+}
+
+type Tracing struct {
+	Enable   bool   `yaml:"enable" valid:"type(bool)"`
+	Endpoint string `yaml:"endpoint,omitempty" valid:"optional"`
+	Insecure bool   `yaml:"insecure" valid:"type(bool)"`
+	// SamplingRatio is the fraction of new traces sampled. Defaults to 1.
+	SamplingRatio *float64 `yaml:"samplingRatio,omitempty" valid:"optional"`
+}
+
+type Maintenance struct {
+	Enable     bool   `yaml:"enable" valid:"type(bool)"`
+	Message    string `yaml:"message,omitempty" valid:"optional"`
+	RetryAfter int    `yaml:"retryAfter,omitempty" valid:"optional"`
+}
+
+type Limiter struct {
+	MaxInflight  int `yaml:"maxInflight" valid:"optional"`
+	MaxQueue     int `yaml:"maxQueue" valid:"optional"`
+	QueueTimeout int `yaml:"queueTimeout" valid:"optional"`
+	RetryAfter   int `yaml:"retryAfter,omitempty" valid:"optional"`
+}
+
+// Tap keeps the last requests in memory for GET /admin/tap.
+type Tap struct {
+	Enable bool `yaml:"enable" valid:"type(bool)"`
+	Size   int  `yaml:"size,omitempty" valid:"optional"`
+}
+
+// Drain configures POST /admin/drain. GracePeriod is in milliseconds, RetryAfter in seconds.
+type Drain struct {
+	GracePeriod int `yaml:"gracePeriod,omitempty" valid:"optional"`
+	RetryAfter  int `yaml:"retryAfter,omitempty" valid:"optional"`
+}
+
+// Client configures every outbound HTTP client. Timeouts are in milliseconds, zero means no limit.
+type Client struct {
+	Timeout             int     `yaml:"timeout,omitempty" valid:"optional"`
+	DialTimeout         int     `yaml:"dialTimeout,omitempty" valid:"optional"`
+	TLSHandshakeTimeout int     `yaml:"tlsHandshakeTimeout,omitempty" valid:"optional"`
+	Tls                 *Tls    `yaml:"tls,omitempty" valid:"optional"`
+	CABundle            string  `yaml:"caBundle,omitempty" valid:"optional"`
+	Proxy               string  `yaml:"proxy,omitempty" valid:"optional,url"`
+	MaxIdleConns        int     `yaml:"maxIdleConns,omitempty" valid:"optional"`
+	MaxIdleConnsPerHost int     `yaml:"maxIdleConnsPerHost,omitempty" valid:"optional"`
+	MaxConnsPerHost     int     `yaml:"maxConnsPerHost,omitempty" valid:"optional"`
+	Egress              *Egress `yaml:"egress,omitempty" valid:"optional"`
+}
+
+// Egress limits the hosts outbound requests may reach. Entries are host names, "*.domain" wildcards,
+// IPs or CIDRs. Denied entries always block; when Allow is set, only its entries may be reached.
+type Egress struct {
+	Allow []string `yaml:"allow,omitempty" valid:"optional"`
+	Deny  []string `yaml:"deny,omitempty" valid:"optional"`
+}
+
+type Dependency struct {
+	Name    string `yaml:"name" valid:"type(string),minstringlength(1),required"`
+	Address string `yaml:"address" valid:"dialstring,required"`
+	Policy  string `yaml:"policy" valid:"required,in(required|optional)"`
+	Timeout int    `yaml:"timeout,omitempty" valid:"optional"`
+}
+
+type Seed struct {
+	Characters []SeedCharacter `yaml:"characters,omitempty" json:"characters" valid:"optional"`
+	File       string          `yaml:"file,omitempty" json:"-" valid:"optional"`
+}
+
+type SeedCharacter struct {
+	FirstName string `yaml:"firstName" json:"firstName" valid:"required"`
+	LastName  string `yaml:"lastName" json:"lastName" valid:"required"`
 }
 
 type Tls struct {
@@ -59,6 +179,12 @@ func (c *Config) Validate() (bool, error) {
 		}
 	}
 
+	if l := c.Logger; l != nil {
+		if result, err := l.validate(); err != nil {
+			return result, err
+		}
+	}
+
 	result, err := govalidator.ValidateStruct(c)
 	return result, appendInvalid(err)
 }
@@ -69,6 +195,45 @@ func (c *Configuration) validate() (bool, error) {
 			return result, err
 		}
 	}
+
+	if limiter := c.Limiter; limiter != nil {
+		if limiter.MaxInflight < 0 || limiter.MaxQueue < 0 || limiter.QueueTimeout < 0 || limiter.RetryAfter < 0 {
+			return false, govalidator.Errors{fmt.Errorf("invalid limiter: values must not be negative")}
+		}
+	}
+
+	if tap := c.Tap; tap != nil && tap.Size < 0 {
+		return false, govalidator.Errors{fmt.Errorf("invalid tap size: %d must not be negative", tap.Size)}
+	}
+
+	if client := c.Client; client != nil {
+		if result, err := client.validate(); err != nil {
+			return result, err
+		}
+	}
+
+	names := map[string]bool{}
+	for _, dependency := range c.Dependencies {
+		if names[dependency.Name] {
+			return false, govalidator.Errors{fmt.Errorf("invalid dependencies: duplicated name [%s]", dependency.Name)}
+		}
+		names[dependency.Name] = true
+		if dependency.Timeout < 0 {
+			return false, govalidator.Errors{fmt.Errorf("invalid dependencies: [%s] timeout must not be negative", dependency.Name)}
+		}
+	}
+
+	switch c.FeatureDisabledStatus {
+	case 0, http.StatusNotFound, http.StatusNotImplemented:
+	default:
+		return false, govalidator.Errors{fmt.Errorf("invalid featureDisabledStatus: %d is not 404 or 501", c.FeatureDisabledStatus)}
+	}
+
+	if tracing := c.Tracing; tracing != nil {
+		if result, err := tracing.validate(); err != nil {
+			return result, err
+		}
+	}
 	result, err := govalidator.ValidateStruct(c)
 	return result, appendInvalid(err)
 }
@@ -84,10 +249,81 @@ func (s *Sbi) validate() (bool, error) {
 		}
 	}
 
+	if s.MaxRequestTimeout < 0 {
+		return false, govalidator.Errors{fmt.Errorf("invalid maxRequestTimeout: %d must not be negative", s.MaxRequestTimeout)}
+	}
+
+	for _, proxy := range s.TrustedProxies {
+		if !govalidator.IsCIDR(proxy) && !govalidator.IsIP(proxy) {
+			return false, govalidator.Errors{fmt.Errorf("invalid trustedProxies: %s is not an IP or CIDR", proxy)}
+		}
+	}
+
 	result, err := govalidator.ValidateStruct(s)
 	return result, appendInvalid(err)
 }
 
+func (l *Logger) validate() (bool, error) {
+	modules := logger.Modules()
+	for module, level := range l.Modules {
+		if !slices.Contains(modules, module) {
+			return false, govalidator.Errors{fmt.Errorf("invalid logger modules: unknown module [%s]", module)}
+		}
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return false, govalidator.Errors{fmt.Errorf("invalid logger modules: %s level %w", module, err)}
+		}
+	}
+
+	if access := l.Access; access != nil && (access.SampleRate < 0 || access.SlowThreshold < 0) {
+		return false, govalidator.Errors{fmt.Errorf("invalid logger access: sampleRate and slowThreshold must not be negative")}
+	}
+
+	if file := l.File; file != nil {
+		if file.MaxSize < 0 || file.MaxBackups < 0 {
+			return false, govalidator.Errors{fmt.Errorf("invalid logger file: maxSize and maxBackups must not be negative")}
+		}
+		result, err := govalidator.ValidateStruct(file)
+		return result, appendInvalid(err)
+	}
+	return true, nil
+}
+
+func (t *Tracing) validate() (bool, error) {
+	if ratio := t.GetSamplingRatio(); ratio < 0 || ratio > 1 {
+		return false, govalidator.Errors{fmt.Errorf("invalid tracing samplingRatio: %v is not in [0, 1]", ratio)}
+	}
+	if t.Enable && t.Endpoint == "" {
+		return false, govalidator.Errors{fmt.Errorf("invalid tracing endpoint: required when tracing is enabled")}
+	}
+	return true, nil
+}
+
+func (c *Client) validate() (bool, error) {
+	for _, value := range []int{
+		c.Timeout, c.DialTimeout, c.TLSHandshakeTimeout,
+		c.MaxIdleConns, c.MaxIdleConnsPerHost, c.MaxConnsPerHost,
+	} {
+		if value < 0 {
+			return false, govalidator.Errors{fmt.Errorf("invalid client: timeouts and limits must not be negative")}
+		}
+	}
+
+	if egress := c.Egress; egress != nil {
+		if c.Proxy != "" {
+			return false, govalidator.Errors{fmt.Errorf("invalid client: proxy cannot be combined with egress")}
+		}
+		for _, entry := range append(append([]string{}, egress.Allow...), egress.Deny...) {
+			host := strings.TrimPrefix(entry, "*.")
+			if !govalidator.IsCIDR(entry) && !govalidator.IsIP(entry) && !govalidator.IsDNSName(host) {
+				return false, govalidator.Errors{fmt.Errorf("invalid client egress: %s is not a host name, IP or CIDR", entry)}
+			}
+		}
+	}
+
+	result, err := govalidator.ValidateStruct(c)
+	return result, appendInvalid(err)
+}
+
 func (t *Tls) validate() (bool, error) {
 	result, err := govalidator.ValidateStruct(t)
 	return result, err
@@ -118,6 +354,69 @@ func (c *Config) GetVersion() string {
 	return ""
 }
 
+func (c *Config) GetTracing() *Tracing {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil || c.Configuration.Tracing == nil {
+		return &Tracing{}
+	}
+	return c.Configuration.Tracing
+}
+
+// GetSamplingRatio returns the configured sampling ratio, or 1 when it is omitted
+// so enabling tracing alone records every trace.
+func (t *Tracing) GetSamplingRatio() float64 {
+	if t.SamplingRatio == nil {
+		return 1
+	}
+	return *t.SamplingRatio
+}
+
+func (c *Config) GetLimiter() *Limiter {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil || c.Configuration.Limiter == nil {
+		return &Limiter{}
+	}
+	return c.Configuration.Limiter
+}
+
+func (c *Config) GetTap() *Tap {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil || c.Configuration.Tap == nil {
+		return &Tap{}
+	}
+	return c.Configuration.Tap
+}
+
+func (c *Config) GetDrain() *Drain {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil || c.Configuration.Drain == nil {
+		return &Drain{}
+	}
+	return c.Configuration.Drain
+}
+
+func (c *Config) GetClient() *Client {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil || c.Configuration.Client == nil {
+		return &Client{}
+	}
+	return c.Configuration.Client
+}
+
+func (c *Config) GetDependencies() []Dependency {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Configuration == nil {
+		return nil
+	}
+	return c.Configuration.Dependencies
+}
+
 func (c *Config) SetLogEnable(enable bool) {
 	c.Lock()
 	defer c.Unlock()
@@ -182,6 +481,37 @@ func (c *Config) GetLogLevel() string {
 	return c.Logger.Level
 }
 
+func (c *Config) GetLogModuleLevels() map[string]string {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Logger == nil {
+		return nil
+	}
+	levels := make(map[string]string, len(c.Logger.Modules))
+	for module, level := range c.Logger.Modules {
+		levels[module] = level
+	}
+	return levels
+}
+
+func (c *Config) GetLogAccess() *AccessLog {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Logger == nil || c.Logger.Access == nil {
+		return &AccessLog{}
+	}
+	return c.Logger.Access
+}
+
+func (c *Config) GetLogFile() *LogFile {
+	c.RLock()
+	defer c.RUnlock()
+	if c.Logger == nil {
+		return nil
+	}
+	return c.Logger.File
+}
+
 func (c *Config) GetLogReportCaller() bool {
 	c.RLock()
 	defer c.RUnlock()
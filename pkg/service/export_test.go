@@ -0,0 +1,14 @@
+package service
+
+import (
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/lifecycle"
+)
+
+func (a *NfApp) Lifecycle() *lifecycle.Registry {
+	return a.lifecycle
+}
+
+func (a *NfApp) SetContext(nfCtx *nf_context.NFContext) {
+	a.nfCtx = nfCtx
+}
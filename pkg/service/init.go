@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"runtime/debug"
 	"sync"
 
 	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/health"
+	"github.com/Alonza0314/nf-example/internal/lifecycle"
 	"github.com/Alonza0314/nf-example/internal/logger"
 	"github.com/Alonza0314/nf-example/internal/sbi"
+	"github.com/Alonza0314/nf-example/internal/sbi/consumer"
 	"github.com/Alonza0314/nf-example/internal/sbi/processor"
+	"github.com/Alonza0314/nf-example/internal/tracing"
 	"github.com/Alonza0314/nf-example/pkg/app"
 	"github.com/Alonza0314/nf-example/pkg/factory"
 	"github.com/sirupsen/logrus"
@@ -24,14 +29,20 @@ type NfApp struct {
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 
-	sbiServer *sbi.Server
-	processor *processor.Processor
+	sbiServer     *sbi.Server
+	processor     *processor.Processor
+	clientFactory *consumer.ClientFactory
+
+	lifecycle *lifecycle.Registry
 }
 
 var _ app.App = &NfApp{}
 
 func NewApp(ctx context.Context, cfg *factory.Config, tlsKeyLogPath string) (*NfApp, error) {
 	nf_context.InitNfContext()
+	if err := nf_context.GetSelf().LoadSeed(cfg.Configuration.Seed); err != nil {
+		return nil, fmt.Errorf("load seed failed: %w", err)
+	}
 
 	nf := &NfApp{
 		cfg:   cfg,
@@ -42,11 +53,62 @@ func NewApp(ctx context.Context, cfg *factory.Config, tlsKeyLogPath string) (*Nf
 	nf.SetLogEnable(cfg.GetLogEnable())
 	nf.SetLogLevel(cfg.GetLogLevel())
 	nf.SetReportCaller(cfg.GetLogReportCaller())
+	nf.setModuleLogLevels(cfg.GetLogModuleLevels())
+	if err := nf.setLogFile(cfg.GetLogFile()); err != nil {
+		return nf, err
+	}
 
 	nf.ctx, nf.cancel = context.WithCancel(ctx)
 
+	clientFactory, err := consumer.NewClientFactory(cfg.GetClient())
+	if err != nil {
+		return nf, err
+	}
+	nf.clientFactory = clientFactory
+
+	shutdownTracing, err := tracing.Init(nf.ctx, cfg.GetTracing(), nf.nfCtx.Name, nf.nfCtx.NfId,
+		clientFactory.UntracedClient())
+	if err != nil {
+		return nf, err
+	}
+	nf.lifecycle = lifecycle.NewRegistry()
+	nf.lifecycle.Register(lifecycle.Hook{
+		Name:  "tracing",
+		Order: 0,
+		Stop:  shutdownTracing,
+	})
+
+	nf.nfCtx.Health = health.NewChecker(health.FromConfig(cfg))
+	if err = nf.nfCtx.Health.Startup(nf.ctx); err != nil {
+		return nf, fmt.Errorf("startup dependency check failed: %w", err)
+	}
+	nf.lifecycle.Register(lifecycle.Hook{
+		Name:  "health",
+		Order: 1,
+		Stop: func(context.Context) error {
+			// retries end with the app context, which is canceled before shutdown
+			nf.nfCtx.Health.Wait()
+			return nil
+		},
+	})
+
 	sbiServer := sbi.NewServer(nf, tlsKeyLogPath)
 	nf.sbiServer = sbiServer
+	nf.lifecycle.Register(lifecycle.Hook{
+		Name:  "sbi",
+		Order: 2,
+		Start: func(context.Context) error {
+			if err := nf.nfCtx.CheckInitialized(); err != nil {
+				return err
+			}
+			sbiServer.Run(&nf.wg)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			sbiServer.Shutdown()
+			return nil
+		},
+	})
 
 	processor, err := processor.NewProcessor(nf)
 	if err != nil {
@@ -57,6 +119,11 @@ func NewApp(ctx context.Context, cfg *factory.Config, tlsKeyLogPath string) (*Nf
 	return nf, nil
 }
 
+// ClientFactory returns the factory every outbound HTTP client must come from.
+func (a *NfApp) ClientFactory() *consumer.ClientFactory {
+	return a.clientFactory
+}
+
 func (a *NfApp) Config() *factory.Config {
 	return a.cfg
 }
@@ -78,9 +145,9 @@ func (a *NfApp) SetLogEnable(enable bool) {
 	}
 	a.cfg.SetLogEnable(enable)
 	if enable {
-		logger.Log.SetOutput(os.Stderr)
+		logger.SetOutput(os.Stderr)
 	} else {
-		logger.Log.SetOutput(io.Discard)
+		logger.SetOutput(io.Discard)
 	}
 }
 
@@ -95,7 +162,35 @@ func (a *NfApp) SetLogLevel(level string) {
 		return
 	}
 	a.cfg.SetLogLevel(level)
-	logger.Log.SetLevel(lvl)
+	logger.SetLevel(lvl)
+}
+
+func (a *NfApp) setModuleLogLevels(levels map[string]string) {
+	for module, level := range levels {
+		lvl, err := logrus.ParseLevel(level)
+		if err != nil {
+			logger.MainLog.Warnf("Log level [%s] of module [%s] is invalid", level, module)
+			continue
+		}
+		if err = logger.SetModuleLevel(module, lvl); err != nil {
+			logger.MainLog.Warnf("Set log level failed: %+v", err)
+			continue
+		}
+		logger.MainLog.Infof("Log level of module [%s] is set to [%s]", module, level)
+	}
+}
+
+func (a *NfApp) setLogFile(file *factory.LogFile) error {
+	if file == nil {
+		return nil
+	}
+	const megabyte = 1 << 20
+	if _, _, err := logger.AddFileOutput(file.Path, int64(file.MaxSize)*megabyte, file.MaxBackups); err != nil {
+		return err
+	}
+	logger.MainLog.Infof("Log file is set to [%s] (maxSize: %d MB, maxBackups: %d)",
+		file.Path, file.MaxSize, file.MaxBackups)
+	return nil
 }
 
 func (a *NfApp) SetReportCaller(reportCaller bool) {
@@ -104,20 +199,25 @@ func (a *NfApp) SetReportCaller(reportCaller bool) {
 		return
 	}
 	a.cfg.SetLogReportCaller(reportCaller)
-	logger.Log.SetReportCaller(reportCaller)
+	logger.SetReportCaller(reportCaller)
 }
 
-func (a *NfApp) Start() {
+// Start starts every component and blocks until the app terminates. It returns the error of a
+// component that failed to start, after stopping the ones already started.
+func (a *NfApp) Start() error {
 	defer func() {
 		if p := recover(); p != nil {
 			logger.InitLog.Fatalf("panic: %v\n%s", p, string(debug.Stack()))
 		}
 	}()
 
-	a.sbiServer.Run(&a.wg)
+	if err := a.lifecycle.Start(a.ctx); err != nil {
+		return fmt.Errorf("start failed: %w", err)
+	}
 
 	go a.listenShutdown(a.ctx)
 	a.Wait()
+	return nil
 }
 
 func (a *NfApp) listenShutdown(ctx context.Context) {
@@ -131,7 +231,9 @@ func (a *NfApp) Terminate() {
 
 func (a *NfApp) terminateProcedure() {
 	logger.MainLog.Infof("Terminating ANYA...")
-	a.sbiServer.Shutdown()
+	if err := a.lifecycle.Stop(context.Background()); err != nil {
+		logger.MainLog.Errorf("Shutdown incomplete: %+v", err)
+	}
 }
 
 func (a *NfApp) Wait() {
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	nf_context "github.com/Alonza0314/nf-example/internal/context"
+	"github.com/Alonza0314/nf-example/internal/lifecycle"
+	"github.com/Alonza0314/nf-example/internal/testutil"
+	"github.com/Alonza0314/nf-example/pkg/factory"
+	"github.com/Alonza0314/nf-example/pkg/service"
+	"github.com/gin-gonic/gin"
+)
+
+// newApp builds an app listening on a free loopback port and returns it with its SBI address.
+func newApp(t *testing.T) (*service.NfApp, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %s", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err = listener.Close(); err != nil {
+		t.Fatalf("Failed to close listener: %s", err)
+	}
+
+	cfg := testutil.DefaultConfig()
+	cfg.Configuration.Sbi.Port = port
+	originalConfig := factory.NfConfig
+	factory.NfConfig = cfg
+	t.Cleanup(func() { factory.NfConfig = originalConfig })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	nf, err := service.NewApp(ctx, cfg, "")
+	if err != nil {
+		t.Fatalf("Failed to create app: %s", err)
+	}
+	return nf, net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+func assertNotListening(t *testing.T, addr string) {
+	t.Helper()
+
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		t.Errorf("Expected nothing listening on %s", addr)
+	}
+}
+
+func Test_StartFailingHook(t *testing.T) {
+	nf, addr := newApp(t)
+
+	hookErr := errors.New("hook failed")
+	stopped := false
+	nf.Lifecycle().Register(lifecycle.Hook{
+		Name:  "before-sbi",
+		Order: 1,
+		Start: func(context.Context) error {
+			return hookErr
+		},
+	})
+	nf.Lifecycle().Register(lifecycle.Hook{
+		Name:  "first",
+		Order: 0,
+		Start: func(context.Context) error {
+			return nil
+		},
+		Stop: func(context.Context) error {
+			stopped = true
+			return nil
+		},
+	})
+
+	if err := nf.Start(); !errors.Is(err, hookErr) {
+		t.Fatalf("Expected start to fail with %v, got %v", hookErr, err)
+	}
+	if !stopped {
+		t.Errorf("Expected the hooks already started to be stopped")
+	}
+	assertNotListening(t, addr)
+}
+
+func Test_StartUninitializedContext(t *testing.T) {
+	nf, addr := newApp(t)
+	nf.SetContext(&nf_context.NFContext{})
+
+	if err := nf.Start(); !errors.Is(err, nf_context.ErrContextNotInitialized) {
+		t.Fatalf("Expected start to fail with %v, got %v", nf_context.ErrContextNotInitialized, err)
+	}
+	assertNotListening(t, addr)
+}